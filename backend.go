@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/religiosa1/init-docker-db/dbcreator"
+	"github.com/religiosa1/init-docker-db/engineapi"
+)
+
+// pingTimeout limits the Engine API availability check
+const pingTimeout = 5 * time.Second
+
+// getBackend returns the backend, selected by the --backend flag, exiting if
+// the runtime isn't available. Dry run always uses the CLI backend, as it
+// only prints the commands as a script.
+func getBackend(ctx context.Context, dry DryRun, verbose bool, out io.Writer, suggestDryRun bool) dbcreator.Backend {
+	useAPI := CLI.Backend == "api"
+	if CLI.Backend == "auto" {
+		useAPI = !isRuntimeCLIAvailable()
+	}
+	if dry.Enabled() || !useAPI {
+		runtime := getRuntime(dry.Enabled(), suggestDryRun)
+		shell := dbcreator.NewShell(dry.Enabled(), verbose).
+			WithRuntime(runtime).
+			WithOutput(out).
+			WithScriptDialect(dry.Dialect())
+		return dbcreator.NewCLIBackend(shell)
+	}
+
+	backend, err := newAPIBackend(ctx, verbose, out)
+	if err != nil {
+		if CLI.Backend == "auto" {
+			err = fmt.Errorf("none of the container runtimes found in PATH, and %w", err)
+		}
+		exitRuntimeNotFound(err, suggestDryRun)
+	}
+	return backend
+}
+
+func isRuntimeCLIAvailable() bool {
+	if CLI.Runtime == "" {
+		_, err := dbcreator.DetectRuntime()
+		return err == nil
+	}
+	runtime, err := dbcreator.RuntimeByName(CLI.Runtime)
+	return err == nil && runtime.IsAvailable()
+}
+
+func newAPIBackend(ctx context.Context, verbose bool, out io.Writer) (dbcreator.Backend, error) {
+	runtime := dbcreator.Docker
+	if CLI.Runtime != "" {
+		var err error
+		if runtime, err = dbcreator.RuntimeByName(CLI.Runtime); err != nil {
+			return nil, err
+		}
+	}
+	if runtime.Name == dbcreator.Nerdctl.Name {
+		return nil, fmt.Errorf("nerdctl doesn't provide the Engine API, use --backend cli instead")
+	}
+
+	defaultHost := engineapi.DefaultHost
+	if runtime.Name == dbcreator.Podman.Name {
+		defaultHost = engineapi.DefaultPodmanHost
+	}
+	host, err := defaultHost()
+	if err != nil {
+		return nil, err
+	}
+	client, err := engineapi.NewClient(host)
+	if err != nil {
+		return nil, err
+	}
+	pingCtx, cancel := context.WithTimeout(ctx, pingTimeout)
+	defer cancel()
+	if err := client.Ping(pingCtx); err != nil {
+		return nil, fmt.Errorf("engine API isn't reachable at %s: %w", host, err)
+	}
+	return dbcreator.NewAPIBackend(client, runtime, verbose, out), nil
+}
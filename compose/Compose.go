@@ -0,0 +1,167 @@
+// Package compose exports database service definitions into compose files,
+// preserving other services, volumes and comments already present in them
+package compose
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/religiosa1/init-docker-db/dbcreator"
+)
+
+// Project is a set of services and named volumes to be written into a compose
+// file
+type Project struct {
+	Services []Service
+	Volumes  []string
+}
+
+// Service is a subset of the compose service definition
+type Service struct {
+	Name        string       `yaml:"-"`
+	Image       string       `yaml:"image"`
+	Hostname    string       `yaml:"hostname,omitempty"`
+	User        string       `yaml:"user,omitempty"`
+	Entrypoint  []string     `yaml:"entrypoint,omitempty"`
+	Command     []string     `yaml:"command,omitempty"`
+	Environment []string     `yaml:"environment,omitempty"`
+	Ports       []string     `yaml:"ports,omitempty"`
+	Volumes     []string     `yaml:"volumes,omitempty"`
+	UsernsMode  string       `yaml:"userns_mode,omitempty"`
+	Healthcheck *Healthcheck `yaml:"healthcheck,omitempty"`
+	DependsOn   DependsOn    `yaml:"depends_on,omitempty"`
+}
+
+// Healthcheck is a compose service health check
+type Healthcheck struct {
+	Test        []string `yaml:"test"`
+	Interval    string   `yaml:"interval"`
+	Timeout     string   `yaml:"timeout"`
+	Retries     int      `yaml:"retries"`
+	StartPeriod string   `yaml:"start_period"`
+}
+
+// DependsOn maps names of the services to the conditions, they must meet
+// before the service is started
+type DependsOn map[string]Condition
+
+type Condition struct {
+	Condition string `yaml:"condition"`
+}
+
+// Options of the definition conversion
+type Options struct {
+	// runtime, whose quirks the compose implementation follows, e.g. podman-compose
+	Runtime dbcreator.Runtime
+	// directory of the compose file, bind mounts inside of it are written as
+	// relative paths, so the file can be committed
+	BaseDir string
+	// time for the database to initialize, during which failed health checks
+	// aren't counted
+	StartPeriod time.Duration
+}
+
+const (
+	healthcheckInterval = 5 * time.Second
+	healthcheckTimeout  = 10 * time.Second
+	healthcheckRetries  = 5
+)
+
+// FromDefinition converts the service definition into the compose project.
+// Labels of the tool aren't written, as the services are managed by compose.
+func FromDefinition(def dbcreator.ServiceDefinition, opts Options) Project {
+	var project Project
+	db := fromSpec(def.Spec, opts, &project)
+	if len(def.HealthCheck) > 0 {
+		if opts.StartPeriod == 0 {
+			opts.StartPeriod = dbcreator.DefaultTimeout
+		}
+		db.Healthcheck = &Healthcheck{
+			Test:        append([]string{"CMD"}, escapeAll(def.HealthCheck)...),
+			Interval:    formatDuration(healthcheckInterval),
+			Timeout:     formatDuration(healthcheckTimeout),
+			Retries:     healthcheckRetries,
+			StartPeriod: formatDuration(opts.StartPeriod),
+		}
+	}
+	project.Services = append(project.Services, db)
+
+	if def.Init != nil {
+		initService := fromSpec(*def.Init, opts, &project)
+		initService.DependsOn = DependsOn{db.Name: {Condition: "service_healthy"}}
+		project.Services = append(project.Services, initService)
+	}
+	return project
+}
+
+// fromSpec converts the container spec into a service, registering its named
+// volumes in the project
+func fromSpec(spec dbcreator.ContainerSpec, opts Options, project *Project) Service {
+	service := Service{
+		Name:        spec.Name,
+		Image:       opts.Runtime.Image(spec.Image, spec.Tag),
+		Hostname:    spec.Hostname,
+		User:        spec.User,
+		Command:     escapeAll(spec.Cmd),
+		Environment: escapeAll(spec.Env),
+	}
+	if spec.Entrypoint != "" {
+		service.Entrypoint = []string{escape(spec.Entrypoint)}
+	}
+	for _, binding := range spec.PortBindings {
+		service.Ports = append(service.Ports, fmt.Sprintf("%s:%d", binding, spec.Port))
+	}
+	for _, mount := range spec.Mounts {
+		if mount.IsBind() {
+			mount.Source = relativeSource(mount.Source, opts.BaseDir)
+		} else {
+			project.Volumes = append(project.Volumes, mount.Source)
+		}
+		service.Volumes = append(service.Volumes, escape(mount.String()))
+	}
+	if opts.Runtime.KeepIDForBindMounts && spec.HasBindMounts() {
+		service.UsernsMode = "keep-id"
+	}
+	return service
+}
+
+// relativeSource makes the bind mount source relative to the compose file
+// directory, if it's located inside of it
+func relativeSource(source string, baseDir string) string {
+	if baseDir == "" {
+		return source
+	}
+	rel, err := filepath.Rel(baseDir, source)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return source
+	}
+	return "./" + filepath.ToSlash(rel)
+}
+
+// formatDuration formats whole seconds without minutes, e.g. "90s" instead
+// of "1m30s", as it's more common in compose files
+func formatDuration(d time.Duration) string {
+	if d%time.Second == 0 {
+		return fmt.Sprintf("%ds", d/time.Second)
+	}
+	return d.String()
+}
+
+// escape prevents compose from interpolating variables in the value, e.g. in
+// passwords or in the shell scripts
+func escape(value string) string {
+	return strings.ReplaceAll(value, "$", "$$")
+}
+
+func escapeAll(values []string) []string {
+	if values == nil {
+		return nil
+	}
+	escaped := make([]string, len(values))
+	for i, v := range values {
+		escaped[i] = escape(v)
+	}
+	return escaped
+}
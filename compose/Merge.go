@@ -0,0 +1,145 @@
+package compose
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Merge writes the project into the compose file at path, creating it if it
+// doesn't exist. Services with the same names are replaced, while any other
+// services, volumes, top-level keys and comments are preserved.
+func Merge(path string, project Project) error {
+	var perm fs.FileMode = 0o644
+	content, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		if stat, err := os.Stat(path); err == nil {
+			perm = stat.Mode().Perm()
+		}
+	case !errors.Is(err, fs.ErrNotExist):
+		return fmt.Errorf("error reading compose file: %w", err)
+	}
+
+	merged, err := Render(content, project)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, merged, perm); err != nil {
+		return fmt.Errorf("error writing compose file: %w", err)
+	}
+	return nil
+}
+
+// Render merges the project into the existing compose file content, which
+// can be empty
+func Render(content []byte, project Project) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, fmt.Errorf("error parsing compose file: %w", err)
+	}
+	if doc.Kind == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, errors.New("error parsing compose file: top-level value must be a mapping")
+	}
+
+	services, err := mappingValue(root, "services")
+	if err != nil {
+		return nil, err
+	}
+	for _, service := range project.Services {
+		var node yaml.Node
+		if err := node.Encode(service); err != nil {
+			return nil, fmt.Errorf("error encoding service '%s': %w", service.Name, err)
+		}
+		setFlowStyle(&node, "test", "entrypoint")
+		setMappingValue(services, service.Name, &node)
+	}
+
+	if len(project.Volumes) > 0 {
+		volumes, err := mappingValue(root, "volumes")
+		if err != nil {
+			return nil, err
+		}
+		for _, volume := range project.Volumes {
+			// existing volume configuration, e.g. a driver, is kept as is
+			if findKey(volumes, volume) == -1 {
+				setMappingValue(volumes, volume, &yaml.Node{Kind: yaml.MappingNode, Style: yaml.FlowStyle})
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, fmt.Errorf("error encoding compose file: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// mappingValue returns the mapping under the key, creating it if it's missing
+// or empty
+func mappingValue(mapping *yaml.Node, key string) (*yaml.Node, error) {
+	if i := findKey(mapping, key); i != -1 {
+		value := mapping.Content[i+1]
+		if value.Kind == yaml.MappingNode {
+			return value, nil
+		}
+		if value.Kind != yaml.ScalarNode || value.Tag != "!!null" {
+			return nil, fmt.Errorf("error parsing compose file: '%s' must be a mapping", key)
+		}
+		*value = yaml.Node{Kind: yaml.MappingNode}
+		return value, nil
+	}
+	value := &yaml.Node{Kind: yaml.MappingNode}
+	setMappingValue(mapping, key, value)
+	return value, nil
+}
+
+// setMappingValue replaces the value of the key, keeping its comments, or
+// appends the key to the mapping
+func setMappingValue(mapping *yaml.Node, key string, value *yaml.Node) {
+	if i := findKey(mapping, key); i != -1 {
+		value.HeadComment = mapping.Content[i+1].HeadComment
+		mapping.Content[i+1] = value
+		return
+	}
+	keyNode := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}
+	mapping.Content = append(mapping.Content, keyNode, value)
+}
+
+// setFlowStyle writes short command lists of the keys in a single line, e.g.
+// `test: [CMD, pg_isready]`
+func setFlowStyle(node *yaml.Node, keys ...string) {
+	if node.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		value := node.Content[i+1]
+		if value.Kind == yaml.SequenceNode && slices.Contains(keys, node.Content[i].Value) {
+			value.Style = yaml.FlowStyle
+		}
+		setFlowStyle(value, keys...)
+	}
+}
+
+func findKey(mapping *yaml.Node, key string) int {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,82 @@
+package compose
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/religiosa1/init-docker-db/dbcreator"
+)
+
+func TestRender(t *testing.T) {
+	project := Project{
+		Services: []Service{{Name: "db", Image: "postgres:16", Volumes: []string{"pgdata:/var/lib/postgresql/data"}}},
+		Volumes:  []string{"pgdata"},
+	}
+
+	t.Run("creates a new file", func(t *testing.T) {
+		got, err := Render(nil, project)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "services:\n  db:\n    image: postgres:16\n    volumes:\n      - pgdata:/var/lib/postgresql/data\nvolumes:\n  pgdata: {}\n"
+		if string(got) != want {
+			t.Errorf("Unexpected value, want\n%s\ngot\n%s", want, got)
+		}
+	})
+
+	t.Run("keeps other services and comments", func(t *testing.T) {
+		existing := "# app stack\nservices:\n  app:\n    image: app # our app\n  db:\n    image: mysql\nvolumes:\n  pgdata:\n    driver: local\n"
+		got, err := Render([]byte(existing), project)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, s := range []string{"# app stack", "image: app # our app", "image: postgres:16", "driver: local"} {
+			if !strings.Contains(string(got), s) {
+				t.Errorf("Expected %q in the result, got\n%s", s, got)
+			}
+		}
+		if strings.Contains(string(got), "mysql") {
+			t.Errorf("Expected the service with the same name to be replaced, got\n%s", got)
+		}
+	})
+
+	t.Run("rejects malformed files", func(t *testing.T) {
+		if _, err := Render([]byte("services: [db]\n"), project); err == nil {
+			t.Error("expected Render to throw, but it didn't")
+		}
+	})
+}
+
+func TestFromDefinition(t *testing.T) {
+	def := dbcreator.ServiceDefinition{
+		Spec: dbcreator.ContainerSpec{
+			Name:         "db",
+			Image:        "mcr.microsoft.com/mssql/server",
+			Tag:          "2022-latest",
+			Env:          []string{"MSSQL_SA_PASSWORD=pa$$word"},
+			Port:         1433,
+			PortBindings: []string{"127.0.0.1:1433"},
+			Mounts:       []dbcreator.Mount{{Source: "/srv/app/data", Target: "/var/opt/mssql"}},
+		},
+		HealthCheck: []string{"sqlcmd", "-Q", "SELECT 1"},
+		Init:        &dbcreator.ContainerSpec{Name: "db-init", Image: "mcr.microsoft.com/mssql/server", Tag: "2022-latest"},
+	}
+	project := FromDefinition(def, Options{Runtime: dbcreator.Docker, BaseDir: "/srv/app"})
+
+	if len(project.Services) != 2 || len(project.Volumes) != 0 {
+		t.Fatalf("Unexpected project: %+v", project)
+	}
+	db, init := project.Services[0], project.Services[1]
+	if db.Environment[0] != "MSSQL_SA_PASSWORD=pa$$$$word" {
+		t.Errorf("Expected variables to be escaped, got %s", db.Environment[0])
+	}
+	if db.Volumes[0] != "./data:/var/opt/mssql" {
+		t.Errorf("Expected a relative bind mount, got %s", db.Volumes[0])
+	}
+	if db.Ports[0] != "127.0.0.1:1433:1433" || db.Healthcheck == nil || db.Healthcheck.Test[0] != "CMD" {
+		t.Errorf("Unexpected service: %+v", db)
+	}
+	if init.DependsOn["db"].Condition != "service_healthy" {
+		t.Errorf("Expected init to wait for the healthy database, got %+v", init.DependsOn)
+	}
+}
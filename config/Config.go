@@ -0,0 +1,115 @@
+// Package config implements discovery and loading of the project-level and
+// user-level configuration files with named profiles, prefilling CLI flags
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ProjectFileNames are names of the project-level config file, discovered
+// from the current directory upward
+var ProjectFileNames = [...]string{".init-docker-db.yaml", ".init-docker-db.yml"}
+
+// Values are flag values, keyed by the flag name, e.g. "type" or "env-file"
+type Values map[string]any
+
+// File is a single loaded configuration file
+type File struct {
+	// Path to the file itself, relative paths in values are resolved against it
+	Path string `yaml:"-"`
+	// Values applied regardless of the selected profile
+	Values Values `yaml:",inline"`
+	// Named profiles, overriding the top-level values
+	Profiles map[string]Values `yaml:"profiles"`
+}
+
+// Discover finds and loads the project-level config file, searching from the
+// dir upward, and the user-level config file. Returned files are ordered by
+// their precedence, project-level first. Missing files are skipped.
+func Discover(dir string) ([]*File, error) {
+	var files []*File
+
+	projectPath, err := findProjectFile(dir)
+	if err != nil {
+		return nil, err
+	}
+	if projectPath != "" {
+		file, err := Load(projectPath)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+
+	userPath, err := UserFilePath()
+	if err != nil {
+		// no home dir, so no user config
+		return files, nil
+	}
+	file, err := Load(userPath)
+	switch {
+	case err == nil:
+		files = append(files, file)
+	case !errors.Is(err, fs.ErrNotExist):
+		return nil, err
+	}
+	return files, nil
+}
+
+// UserFilePath returns the path of the user-level config file,
+// $XDG_CONFIG_HOME/init-docker-db/config.yaml or ~/.config/init-docker-db/config.yaml
+func UserFilePath() (string, error) {
+	configDir := os.Getenv("XDG_CONFIG_HOME")
+	if configDir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		configDir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(configDir, "init-docker-db", "config.yaml"), nil
+}
+
+// Load reads and parses the config file at path
+func Load(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	file := File{Path: abs}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("error parsing config file '%s': %w", path, err)
+	}
+	return &file, nil
+}
+
+func findProjectFile(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for {
+		for _, name := range ProjectFileNames {
+			path := filepath.Join(dir, name)
+			stat, err := os.Stat(path)
+			if err == nil && !stat.IsDir() {
+				return path, nil
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
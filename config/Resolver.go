@@ -0,0 +1,145 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/alecthomas/kong"
+)
+
+// ProfileFlag is the name of the flag, selecting the profile
+const ProfileFlag = "profile"
+
+// Resolver is a kong resolver, prefilling flag values of the commands from
+// the config files. Flags set on the command line or through environment
+// variables take precedence over the config values. Global flags and flags of
+// other commands, e.g. destroy confirmation, aren't resolved, so a config
+// file, discovered in a parent directory, can't affect them.
+type Resolver struct {
+	files    []*File
+	commands []string
+}
+
+// NewResolver creates a resolver for the files, ordered by their precedence,
+// prefilling flags of the commands
+func NewResolver(files []*File, commands ...string) *Resolver {
+	return &Resolver{files: files, commands: commands}
+}
+
+var _ kong.Resolver = (*Resolver)(nil)
+
+// Validate checks that config files contain only known flags of the commands
+func (r *Resolver) Validate(app *kong.Application) error {
+	known := map[string]bool{}
+	for _, cmd := range app.Children {
+		if cmd.Type != kong.CommandNode || !slices.Contains(r.commands, cmd.Name) {
+			continue
+		}
+		for _, flag := range cmd.Flags {
+			known[flag.Name] = true
+		}
+	}
+	for _, file := range r.files {
+		if err := validateValues(file.Values, known); err != nil {
+			return fmt.Errorf("invalid config file '%s': %w", file.Path, err)
+		}
+		for name, values := range file.Profiles {
+			if err := validateValues(values, known); err != nil {
+				return fmt.Errorf("invalid profile '%s' in config file '%s': %w", name, file.Path, err)
+			}
+		}
+	}
+	return nil
+}
+
+func validateValues(values Values, known map[string]bool) error {
+	for key := range values {
+		if !known[key] || key == ProfileFlag {
+			return fmt.Errorf("unknown option '%s'", key)
+		}
+	}
+	return nil
+}
+
+// Resolve a flag value from the selected profile or top-level values of the
+// config files
+func (r *Resolver) Resolve(ctx *kong.Context, parent *kong.Path, flag *kong.Flag) (any, error) {
+	if flag.Name == ProfileFlag || parent.Command == nil || !slices.Contains(r.commands, parent.Command.Name) {
+		return nil, nil
+	}
+	// environment variables take precedence over the config
+	for _, env := range flag.Envs {
+		if _, ok := os.LookupEnv(env); ok {
+			return nil, nil
+		}
+	}
+
+	profile := selectedProfile(ctx)
+	for _, file := range r.files {
+		if values, ok := file.Profiles[profile]; ok && profile != "" {
+			if value, ok := values[flag.Name]; ok {
+				return file.resolveValue(flag, value), nil
+			}
+		}
+		if value, ok := file.Values[flag.Name]; ok {
+			return file.resolveValue(flag, value), nil
+		}
+	}
+	return nil, nil
+}
+
+// HasProfile reports whether any of the config files contains the profile
+func (r *Resolver) HasProfile(profile string) bool {
+	return slices.ContainsFunc(r.files, func(file *File) bool {
+		_, ok := file.Profiles[profile]
+		return ok
+	})
+}
+
+func selectedProfile(ctx *kong.Context) string {
+	for _, flag := range ctx.Flags() {
+		if flag.Name == ProfileFlag {
+			profile, _ := ctx.FlagValue(flag).(string)
+			return profile
+		}
+	}
+	return ""
+}
+
+// kong types of the flags, whose values are paths
+var pathTypes = []string{"path", "existingfile", "existingdir"}
+
+// resolveValue converts the value into string(s) and makes relative paths in
+// it relative to the config file location instead of the current directory.
+// Values of `path`, `existingfile` and `existingdir` flags are always treated
+// as paths, for other flags only values starting with "./" or "../".
+func (f *File) resolveValue(flag *kong.Flag, value any) any {
+	resolve := func(v any) any {
+		// YAML scalars can be parsed as numbers or booleans, while kong expects
+		// strings for string flags, e.g. `tag: 16`
+		str := fmt.Sprint(v)
+		isPath := slices.Contains(pathTypes, flag.Tag.Type) || strings.HasPrefix(str, "./") || strings.HasPrefix(str, "../")
+		if !isPath || str == "" || filepath.IsAbs(str) {
+			return str
+		}
+		if str == "~" || strings.HasPrefix(str, "~/") {
+			if home, err := os.UserHomeDir(); err == nil {
+				return filepath.Join(home, str[1:])
+			}
+			return str
+		}
+		return filepath.Join(filepath.Dir(f.Path), str)
+	}
+
+	if values, ok := value.([]any); ok {
+		resolved := make([]any, len(values))
+		for i, v := range values {
+			resolved[i] = resolve(v)
+		}
+		return resolved
+	}
+	return resolve(value)
+}
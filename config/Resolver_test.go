@@ -0,0 +1,172 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alecthomas/kong"
+)
+
+type testCli struct {
+	Output  string         `help:""`
+	Create  testCreateArgs `cmd:"" default:"withargs"`
+	Destroy struct {
+		All bool `help:""`
+	} `cmd:""`
+}
+
+type testCreateArgs struct {
+	Profile string   `help:""`
+	Type    string   `help:""`
+	Tag     string   `help:"" env:"TEST_INIT_DOCKER_DB_TAG"`
+	Public  bool     `help:""`
+	Init    []string `type:"path" help:""`
+	Volume  string   `help:""`
+	Conf    string   `type:"existingfile" help:""`
+	Seeds   string   `type:"existingdir" help:""`
+}
+
+const testConfig = `
+tag: 15
+profiles:
+  api:
+    type: postgres
+    public: true
+    init: [./seed, /abs/seed.sql]
+    volume: ./data
+  cache:
+    type: redis
+    volume: cachedata
+  existing:
+    conf: redis.conf
+    seeds: seeds
+`
+
+func parseWithConfig(t *testing.T, args ...string) testCreateArgs {
+	t.Helper()
+	dir := t.TempDir()
+	path := filepath.Join(dir, ProjectFileNames[0])
+	if err := os.WriteFile(path, []byte(testConfig), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "redis.conf"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(dir, "seeds"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	file, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cli testCli
+	parser, err := kong.New(&cli, kong.Resolvers(NewResolver([]*File{file}, "create")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := parser.Parse(args); err != nil {
+		t.Fatal(err)
+	}
+	return cli.Create
+}
+
+func TestResolver(t *testing.T) {
+	t.Run("applies top-level values without a profile", func(t *testing.T) {
+		cli := parseWithConfig(t)
+		if cli.Tag != "15" || cli.Type != "" {
+			t.Errorf("Unexpected values: %+v", cli)
+		}
+	})
+
+	t.Run("applies profile values", func(t *testing.T) {
+		cli := parseWithConfig(t, "--profile", "cache")
+		if cli.Type != "redis" || cli.Tag != "15" || cli.Volume != "cachedata" || cli.Public {
+			t.Errorf("Unexpected values: %+v", cli)
+		}
+	})
+
+	t.Run("flags take precedence over the config", func(t *testing.T) {
+		cli := parseWithConfig(t, "--profile", "cache", "--type", "mongo", "--tag", "7")
+		if cli.Type != "mongo" || cli.Tag != "7" {
+			t.Errorf("Unexpected values: %+v", cli)
+		}
+	})
+
+	t.Run("environment variables take precedence over the config", func(t *testing.T) {
+		t.Setenv("TEST_INIT_DOCKER_DB_TAG", "16")
+		cli := parseWithConfig(t)
+		if cli.Tag != "16" {
+			t.Errorf("Unexpected values: %+v", cli)
+		}
+	})
+
+	t.Run("resolves relative paths against the config file", func(t *testing.T) {
+		cli := parseWithConfig(t, "--profile", "api")
+		if len(cli.Init) != 2 || !filepath.IsAbs(cli.Init[0]) || filepath.Base(cli.Init[0]) != "seed" || cli.Init[1] != "/abs/seed.sql" {
+			t.Errorf("Unexpected init values: %v", cli.Init)
+		}
+		if !filepath.IsAbs(cli.Volume) || filepath.Base(cli.Volume) != "data" {
+			t.Errorf("Unexpected volume value: %s", cli.Volume)
+		}
+	})
+
+	t.Run("resolves existing files and directories against the config file", func(t *testing.T) {
+		// the files aren't present in the current directory, so kong fails, if
+		// they're resolved against it
+		cli := parseWithConfig(t, "--profile", "existing")
+		if !filepath.IsAbs(cli.Conf) || filepath.Base(cli.Conf) != "redis.conf" {
+			t.Errorf("Unexpected conf value: %s", cli.Conf)
+		}
+		if !filepath.IsAbs(cli.Seeds) || filepath.Base(cli.Seeds) != "seeds" {
+			t.Errorf("Unexpected seeds value: %s", cli.Seeds)
+		}
+	})
+}
+
+func TestResolverValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		key  string
+	}{
+		{"unknown flag", "typo"},
+		{"flag of another command", "all"},
+		{"global flag", "output"},
+		{"profile flag", "profile"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := &File{Path: "/foo/.init-docker-db.yaml", Profiles: map[string]Values{
+				"api": {tt.key: "postgres"},
+			}}
+			var cli testCli
+			parser, err := kong.New(&cli, kong.Resolvers(NewResolver([]*File{file}, "create")))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := parser.Parse(nil); err == nil {
+				t.Error("expected unknown option to result in an error, but it didn't")
+			}
+		})
+	}
+}
+
+func TestDiscover(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	root := t.TempDir()
+	nested := filepath.Join(root, "a", "b")
+	if err := os.MkdirAll(nested, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, ProjectFileNames[1]), []byte("type: redis\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := Discover(nested)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 || files[0].Values["type"] != "redis" {
+		t.Errorf("Unexpected files: %+v", files)
+	}
+}
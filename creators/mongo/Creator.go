@@ -2,17 +2,34 @@
 package mongo
 
 import (
+	"context"
 	"fmt"
+	"net/url"
+	"slices"
+	"strings"
 
 	"github.com/religiosa1/init-docker-db/dbcreator"
+	"github.com/religiosa1/init-docker-db/wait"
 )
 
-type Creator struct{}
+type Creator struct {
+	// name of the single-node replica set to initiate, disabled if empty
+	ReplicaSet string
+}
+
+const (
+	port     uint16 = 27017
+	image           = "mongo"
+	dataPath        = "/data/db"
+)
 
-const port uint16 = 27017
+// Extensions of the init scripts, supported by the image's entrypoint
+var initScriptExtensions = []string{".sh", ".js"}
 
 func (c Creator) GetDefaultOpts() dbcreator.DefaultOpts {
 	return dbcreator.DefaultOpts{
+		Image:     image,
+		DataPath:  dataPath,
 		Port:      port,
 		User:      "mongo",
 		DockerTag: "latest",
@@ -24,23 +41,175 @@ func (c Creator) GetCapabilities() dbcreator.Capabilities {
 	return dbcreator.Capabilities{
 		DatabaseName: true,
 		UserPassword: true,
+		RootPassword: true,
+		InitScripts:  true,
 	}
 }
 
-func (c Creator) Create(shell dbcreator.Shell, opts dbcreator.CreateOptions) error {
+func (c Creator) GetServiceDefinition(opts dbcreator.CreateOptions) (dbcreator.ServiceDefinition, error) {
 	// https://hub.docker.com/_/mongo
-	args := []string{
-		"run", "--name", opts.ContainerName,
-		"-e", dbcreator.DockerEnv("MONGO_INITDB_ROOT_PASSWORD", opts.Password),
-		"-e", dbcreator.DockerEnv("MONGO_INITDB_ROOT_USERNAME", opts.User),
-		"-e", dbcreator.DockerEnv("MONGO_INITDB_DATABASE", opts.Database),
+	if c.ReplicaSet != "" && !replicaSetPattern.MatchString(c.ReplicaSet) {
+		return dbcreator.ServiceDefinition{}, ErrInvalidReplicaSet
+	}
+	mounts, err := dbcreator.CreateVolumeMounts(opts.Volume, dataPath)
+	if err != nil {
+		return dbcreator.ServiceDefinition{}, err
+	}
+	scripts, err := dbcreator.ResolveInitScripts(opts.InitScripts, initScriptExtensions)
+	if err != nil {
+		return dbcreator.ServiceDefinition{}, err
+	}
+	rootPassword, err := rootPassword(opts)
+	if err != nil {
+		return dbcreator.ServiceDefinition{}, err
+	}
+	serverPort := c.serverPort(opts)
+	serviceHost := fmt.Sprintf("%s:%d", opts.ContainerName, serverPort)
+	// setup scripts read the credentials from the environment
+	setupEnv := []string{
+		dbcreator.DockerEnv("MONGO_INITDB_ROOT_USERNAME", rootUser),
+		dbcreator.DockerEnv("MONGO_INITDB_ROOT_PASSWORD", rootPassword),
+	}
+	if !isRootUser(opts.User) {
+		setupEnv = append(setupEnv, dbcreator.DockerEnv(appPasswordEnv, opts.Password))
+	}
+	def := dbcreator.ServiceDefinition{
+		Spec: dbcreator.ContainerSpec{
+			Name:         opts.ContainerName,
+			Image:        image,
+			Tag:          opts.DockerTag,
+			Env:          append(slices.Clone(setupEnv), dbcreator.DockerEnv("MONGO_INITDB_DATABASE", opts.Database)),
+			Labels:       dbcreator.CreateLabels("mongo", opts),
+			Port:         serverPort,
+			PortBindings: opts.Ports,
+			Mounts:       append(mounts, dbcreator.CreateInitScriptsMounts(scripts)...),
+		},
+		// The image runs init scripts with a temporary server, listening on
+		// localhost only, so connecting through the container's hostname to
+		// wait for the actual one
+		HealthCheck: []string{"sh", "-c", fmt.Sprintf(`mongosh --quiet --host "$(hostname)" --port %d --eval "db.adminCommand('ping')"`, serverPort)},
 	}
-	args = append(args, dbcreator.CreatePortBindingsArgument(port, opts.Ports)...)
-	args = append(args, "-d", fmt.Sprintf("mongo:%s", opts.DockerTag))
+	var initJS string
+	if c.ReplicaSet != "" {
+		def.Spec.Entrypoint = "sh"
+		def.Spec.Cmd = []string{"-c", replicaSetEntrypoint(c.ReplicaSet, serverPort)}
+		// the definition runs in a compose or cluster network, where the
+		// published address isn't reachable, so the service's one is used
+		initJS += replicaSetJS(c.ReplicaSet, serviceHost) + waitPrimaryJS
+	}
+	if !isRootUser(opts.User) {
+		initJS += userSetupJS(opts)
+	}
+	if initJS != "" {
+		// the setup is performed by a sidecar with the same image, as it
+		// contains mongosh
+		def.Init = &dbcreator.ContainerSpec{
+			Name:       opts.ContainerName + "-init",
+			Image:      image,
+			Tag:        opts.DockerTag,
+			Env:        setupEnv,
+			Entrypoint: "sh",
+			Cmd:        []string{"-c", rootMongosh(serviceHost, initJS)},
+		}
+	}
+	return def, nil
+}
 
-	return shell.Run("docker", args...)
+// serverPort returns the port mongod listens on. Replica set member's host
+// must be reachable both by the clients and by the server itself, so the
+// server listens on the same port, as the published one.
+func (c Creator) serverPort(opts dbcreator.CreateOptions) uint16 {
+	if c.ReplicaSet == "" {
+		return port
+	}
+	return dbcreator.NewConnectionInfo(opts, port).Port
 }
 
-func (c Creator) ValidatePassword(password string) error {
+// memberHost returns the externally reachable address of the replica set
+// member, started by Create
+func (c Creator) memberHost(opts dbcreator.CreateOptions) string {
+	return dbcreator.NewConnectionInfo(opts, port).Address()
+}
+
+func (c Creator) Create(ctx context.Context, backend dbcreator.Backend, tx *dbcreator.Transaction, opts dbcreator.CreateOptions) (string, error) {
+	def, err := c.GetServiceDefinition(opts)
+	if err != nil {
+		return "", err
+	}
+	contID, err := dbcreator.RunContainer(ctx, backend, tx, def.Spec)
+	// waiting regardless of opts.Wait, if the setup is required
+	if err != nil || (!opts.Wait && def.Init == nil) {
+		return contID, err
+	}
+
+	logger := dbcreator.NewProgressLogger(ctx, opts.Verbose, opts.Quiet)
+	defer logger.Done()
+	probe := dbcreator.ExecProbe(backend, contID, def.HealthCheck...)
+	err = tx.Step("waiting for the database", func() error {
+		return dbcreator.WaitForReady(ctx, backend, contID, opts.Timeout, &logger, probe, wait.Opts{})
+	})
+	if err != nil {
+		return contID, err
+	}
+
+	// init sidecar isn't used, as the setup is performed in the container directly
+	host := fmt.Sprintf("localhost:%d", c.serverPort(opts))
+	if c.ReplicaSet != "" {
+		logger.LogState("Initiating the replica set")
+		err = tx.Step("initiating the replica set", func() error {
+			return execMongosh(ctx, backend, contID, rootMongosh(host, replicaSetJS(c.ReplicaSet, c.memberHost(opts))))
+		})
+		if err != nil {
+			return contID, err
+		}
+		probe := dbcreator.ExecProbe(backend, contID, primaryCheck(c.serverPort(opts))...)
+		err = tx.Step("waiting for the primary", func() error {
+			return dbcreator.WaitForReady(ctx, backend, contID, opts.Timeout, &logger, probe, wait.Opts{})
+		})
+		if err != nil {
+			return contID, err
+		}
+	}
+	if isRootUser(opts.User) {
+		return contID, nil
+	}
+	logger.LogState("Creating user")
+	return contID, tx.Step("creating the user", func() error {
+		return execMongosh(ctx, backend, contID, rootMongosh(host, userSetupJS(opts)))
+	})
+}
+
+// execMongosh runs mongosh command in the container, returning its output in
+// the error, as mongosh reports errors to stdout
+func execMongosh(ctx context.Context, backend dbcreator.Backend, contID string, cmd string) error {
+	out, err := backend.Exec(ctx, contID, "sh", "-c", cmd)
+	if err != nil {
+		return fmt.Errorf("%w\n%s", err, strings.TrimSpace(out))
+	}
 	return nil
 }
+
+// ValidatePassword checks the common requirements only, as the image escapes
+// the password itself, and SCRAM authentication prohibits control characters
+func (c Creator) ValidatePassword(password string) error {
+	return dbcreator.CheckPassword(password)
+}
+
+func (c Creator) GetConnectionInfo(opts dbcreator.CreateOptions) dbcreator.ConnectionInfo {
+	info := dbcreator.NewConnectionInfo(opts, port)
+	query := url.Values{"authSource": {authSource(opts)}}
+	if c.ReplicaSet != "" {
+		query.Set("replicaSet", c.ReplicaSet)
+	}
+	uri := url.URL{
+		Scheme:   "mongodb",
+		User:     url.UserPassword(info.User, info.Password),
+		Host:     info.Address(),
+		Path:     "/" + info.Database,
+		RawQuery: query.Encode(),
+	}
+	info.ConnectionStrings = []dbcreator.ConnectionString{
+		{Format: "uri", Value: uri.String()},
+	}
+	return info
+}
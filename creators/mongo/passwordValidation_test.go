@@ -0,0 +1,29 @@
+package mongo
+
+import (
+	"testing"
+
+	"github.com/religiosa1/init-docker-db/dbcreator"
+)
+
+func TestValidatePassword(t *testing.T) {
+	creator := Creator{}
+	tests := []struct {
+		name   string
+		input  string
+		output error
+	}{
+		{"empty password", "", dbcreator.ErrPasswordEmpty},
+		{"new line", "pass\r\nword", dbcreator.ErrPasswordControlChars},
+		{"special chars", `pa$$:@/word'"`, nil},
+		{"short password", "1", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := creator.ValidatePassword(tt.input)
+			if err != tt.output {
+				t.Errorf("Want '%s', got: '%s'", tt.output, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package mongo
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/religiosa1/init-docker-db/dbcreator"
+)
+
+// DefaultReplicaSet is the replica set name, used if the flag has no value
+const DefaultReplicaSet = "rs0"
+
+// keyfile authenticates members of the replica set between each other; it's
+// required with the auth enabled, which is always the case, as the image
+// creates the root user. The node is the only member, so the key is generated
+// anew on each start.
+const keyFilePath = "/tmp/mongo-keyfile"
+
+var ErrInvalidReplicaSet error = errors.New("replica set name can only contain alphanumeric characters, '_', '.' and '-'")
+
+var replicaSetPattern *regexp.Regexp = regexp.MustCompile(`^[\w.-]+$`)
+
+// replicaSetEntrypoint creates a shell script, generating the keyfile and
+// starting mongod as a replica set member through the image's entrypoint
+func replicaSetEntrypoint(name string, port uint16) string {
+	return fmt.Sprintf(
+		"set -e\n"+
+			"head -c 512 /dev/urandom | base64 > %[1]s\n"+
+			"chmod 400 %[1]s\n"+
+			"chown mongodb:mongodb %[1]s\n"+
+			"exec docker-entrypoint.sh mongod --replSet %[2]s --keyFile %[1]s --port %[3]d\n",
+		keyFilePath, dbcreator.Quote(name), port,
+	)
+}
+
+// replicaSetJS initiates the single-node replica set, unless it's already
+// initiated, e.g. in a reused volume. The member host must be reachable by
+// the clients, as they connect to the members from the replica set config
+// instead of the one from the connection string.
+func replicaSetJS(name string, memberHost string) string {
+	return fmt.Sprintf(
+		"try {\n"+
+			"  rs.status();\n"+
+			"} catch (e) {\n"+
+			"  if (e.codeName !== \"NotYetInitialized\") throw e;\n"+
+			"  rs.initiate({ _id: %s, members: [{ _id: 0, host: %s }] });\n"+
+			"}\n",
+		jsString(name), jsString(memberHost),
+	)
+}
+
+// waitPrimaryJS waits for the node to be elected as the primary, as writes
+// are rejected until then
+const waitPrimaryJS = "while (!db.hello().isWritablePrimary) sleep(1000);\n"
+
+// primaryCheck exits with non-zero code, unless the node is the primary
+func primaryCheck(port uint16) []string {
+	return []string{"mongosh", "--quiet", "--port", fmt.Sprint(port), "--eval", "quit(db.hello().isWritablePrimary ? 0 : 1)"}
+}
@@ -0,0 +1,86 @@
+package mongo
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/religiosa1/init-docker-db/dbcreator"
+)
+
+func TestReplicaSet(t *testing.T) {
+	creator := Creator{ReplicaSet: "rs0"}
+	tests := []struct {
+		name       string
+		opts       dbcreator.CreateOptions
+		wantPort   uint16
+		wantMember string
+		wantUser   bool
+	}{
+		{
+			name:       "default port",
+			opts:       dbcreator.CreateOptions{User: "app", Ports: []string{"127.0.0.1:27017"}},
+			wantPort:   27017,
+			wantMember: `host: "mongodb:27017"`,
+			wantUser:   true,
+		},
+		{
+			name:       "custom port",
+			opts:       dbcreator.CreateOptions{User: "root", Ports: []string{"127.0.0.1:27018", "[::1]:27018"}},
+			wantPort:   27018,
+			wantMember: `host: "mongodb:27018"`,
+		},
+		{
+			name:       "specific address",
+			opts:       dbcreator.CreateOptions{User: "root", Ports: []string{"192.168.0.10:27017"}},
+			wantPort:   27017,
+			wantMember: `host: "mongodb:27017"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.ContainerName, tt.opts.Database, tt.opts.Password = "mongodb", "db", "pass"
+			def, err := creator.GetServiceDefinition(tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if def.Spec.Port != tt.wantPort {
+				t.Errorf("Want container port %d, got %d", tt.wantPort, def.Spec.Port)
+			}
+			entrypoint := strings.Join(def.Spec.Cmd, " ")
+			if !strings.Contains(entrypoint, "mongod --replSet rs0 --keyFile "+keyFilePath) {
+				t.Errorf("Unexpected entrypoint script: %s", entrypoint)
+			}
+			if def.Init == nil {
+				t.Fatal("Expected an init container")
+			}
+			script := strings.Join(def.Init.Cmd, " ")
+			for _, want := range []string{tt.wantMember, waitPrimaryJS} {
+				if !strings.Contains(script, want) {
+					t.Errorf("Expected %q in the init script, got\n%s", want, script)
+				}
+			}
+			if strings.Contains(script, "createUser") != tt.wantUser {
+				t.Errorf("Unexpected user creation in the init script:\n%s", script)
+			}
+
+			uri, _ := creator.GetConnectionInfo(tt.opts).ConnectionString("uri")
+			if !strings.Contains(uri, "replicaSet=rs0") {
+				t.Errorf("Expected replicaSet in the URI, got %s", uri)
+			}
+		})
+	}
+
+	t.Run("member host", func(t *testing.T) {
+		opts := dbcreator.CreateOptions{Ports: []string{"192.168.0.10:27018"}}
+		if got := creator.memberHost(opts); got != "192.168.0.10:27018" {
+			t.Errorf("Want the published address as the member host, got %s", got)
+		}
+	})
+
+	t.Run("invalid name", func(t *testing.T) {
+		_, err := Creator{ReplicaSet: "rs/0"}.GetServiceDefinition(dbcreator.CreateOptions{})
+		if err != ErrInvalidReplicaSet {
+			t.Errorf("Want ErrInvalidReplicaSet, got: %v", err)
+		}
+	})
+}
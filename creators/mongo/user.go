@@ -0,0 +1,79 @@
+package mongo
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/religiosa1/init-docker-db/dbcreator"
+)
+
+// rootUser is the name of the root account, created by the image in the admin
+// database. Application user is created separately with access only to the
+// requested database, unless root user itself is requested.
+const rootUser = "root"
+
+// roles of the application user in its database; dbOwner includes readWrite
+// alongside with the index and user administration
+var userRoles = []string{"readWrite", "dbOwner"}
+
+func isRootUser(user string) bool {
+	return user == rootUser
+}
+
+// rootPassword returns the password of the root account, which is the user's
+// one, unless a separate root password is provided
+func rootPassword(opts dbcreator.CreateOptions) (string, error) {
+	if opts.RootPassword == "" {
+		return opts.Password, nil
+	}
+	if isRootUser(opts.User) && opts.RootPassword != opts.Password {
+		return "", dbcreator.ErrRootPasswordWithRootUser
+	}
+	return opts.RootPassword, nil
+}
+
+// authSource returns the database the user is authenticated against
+func authSource(opts dbcreator.CreateOptions) string {
+	if isRootUser(opts.User) {
+		return "admin"
+	}
+	return opts.Database
+}
+
+// appPasswordEnv is the environment variable with the application user's
+// password, so it doesn't show up in the setup command arguments
+const appPasswordEnv = "MONGO_APP_PASSWORD"
+
+// userSetupJS creates the application user in the database, or updates its
+// password and roles if the user already exists, e.g. in a reused volume. The
+// password is read from appPasswordEnv.
+func userSetupJS(opts dbcreator.CreateOptions) string {
+	database, user := jsString(opts.Database), jsString(opts.User)
+	password := "process.env." + appPasswordEnv
+	roles, _ := json.Marshal(userRoles)
+	return fmt.Sprintf(
+		"const appDb = db.getSiblingDB(%s);\n"+
+			"if (appDb.getUser(%s)) {\n"+
+			"  appDb.updateUser(%s, { pwd: %s, roles: %s });\n"+
+			"} else {\n"+
+			"  appDb.createUser({ user: %s, pwd: %s, roles: %s });\n"+
+			"}\n",
+		database, user, user, password, roles, user, password, roles,
+	)
+}
+
+// rootMongosh creates a shell command, running the script with mongosh as
+// root against the server at host. Root credentials are taken from the
+// image's environment variables.
+func rootMongosh(host string, js string) string {
+	return fmt.Sprintf(
+		`mongosh --quiet --host %s -u "$MONGO_INITDB_ROOT_USERNAME" -p "$MONGO_INITDB_ROOT_PASSWORD" --authenticationDatabase admin --eval %s`,
+		dbcreator.Quote(host), dbcreator.Quote(js),
+	)
+}
+
+// jsString formats the string as a JS literal; JSON strings are valid ones
+func jsString(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
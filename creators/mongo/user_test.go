@@ -0,0 +1,89 @@
+package mongo
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/religiosa1/init-docker-db/dbcreator"
+)
+
+func TestUserSetupScript(t *testing.T) {
+	js := userSetupJS(dbcreator.CreateOptions{Database: "app", User: "app\"user", Password: "pa'ss"})
+	script := rootMongosh("mongodb", js)
+	for _, want := range []string{
+		`mongosh --quiet --host mongodb -u "$MONGO_INITDB_ROOT_USERNAME" -p "$MONGO_INITDB_ROOT_PASSWORD" --authenticationDatabase admin`,
+		`db.getSiblingDB("app")`,
+		`appDb.createUser({ user: "app\"user", pwd: process.env.MONGO_APP_PASSWORD, roles: ["readWrite","dbOwner"] })`,
+		`appDb.updateUser("app\"user", { pwd: process.env.MONGO_APP_PASSWORD, roles: ["readWrite","dbOwner"] })`,
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("Expected %q in the script, got\n%s", want, script)
+		}
+	}
+	if strings.Contains(script, "pa'") {
+		t.Errorf("Expected the password to be passed through the env, got\n%s", script)
+	}
+}
+
+func TestUserCreation(t *testing.T) {
+	tests := []struct {
+		user       string
+		wantInit   bool
+		authSource string
+	}{
+		{"app", true, "db"},
+		{"root", false, "admin"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.user, func(t *testing.T) {
+			opts := dbcreator.CreateOptions{ContainerName: "mongodb", Database: "db", User: tt.user, Password: "pass"}
+			def, err := Creator{}.GetServiceDefinition(opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if (def.Init != nil) != tt.wantInit {
+				t.Errorf("Unexpected init container: %v", def.Init)
+			}
+			for _, env := range []string{"MONGO_INITDB_ROOT_USERNAME=root", "MONGO_INITDB_ROOT_PASSWORD=pass"} {
+				if !strings.Contains(strings.Join(def.Spec.Env, "\n"), env) {
+					t.Errorf("Expected %s in the env, got %v", env, def.Spec.Env)
+				}
+			}
+			if tt.wantInit && !slices.Contains(def.Init.Env, "MONGO_APP_PASSWORD=pass") {
+				t.Errorf("Expected the app password in the init env, got %v", def.Init.Env)
+			}
+
+			uri, _ := Creator{}.GetConnectionInfo(opts).ConnectionString("uri")
+			if !strings.HasSuffix(uri, "/db?authSource="+tt.authSource) {
+				t.Errorf("Unexpected authSource in the URI: %s", uri)
+			}
+		})
+	}
+}
+
+func TestRootPassword(t *testing.T) {
+	tests := []struct {
+		name         string
+		user         string
+		rootPassword string
+		want         string
+		err          error
+	}{
+		{"shared with the user", "app", "", "pass", nil},
+		{"separate", "app", "root-pass", "root-pass", nil},
+		{"root user", "root", "", "pass", nil},
+		{"root user with a different root password", "root", "root-pass", "", dbcreator.ErrRootPasswordWithRootUser},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := rootPassword(dbcreator.CreateOptions{User: tt.user, Password: "pass", RootPassword: tt.rootPassword})
+			if err != tt.err {
+				t.Fatalf("Want '%v', got: '%v'", tt.err, err)
+			}
+			if got != tt.want {
+				t.Errorf("Want %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
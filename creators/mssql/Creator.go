@@ -5,7 +5,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/religiosa1/init-docker-db/dbcreator"
@@ -14,10 +13,16 @@ import (
 
 type Creator struct{}
 
-const port uint16 = 1433
+const (
+	port     uint16 = 1433
+	image           = "mcr.microsoft.com/mssql/server"
+	dataPath        = "/var/opt/mssql"
+)
 
 func (c Creator) GetDefaultOpts() dbcreator.DefaultOpts {
 	return dbcreator.DefaultOpts{
+		Image:     image,
+		DataPath:  dataPath,
 		Port:      port,
 		User:      "mssql",
 		DockerTag: "2022-latest",
@@ -29,96 +34,183 @@ func (c Creator) GetCapabilities() dbcreator.Capabilities {
 	return dbcreator.Capabilities{
 		DatabaseName: true,
 		UserPassword: true,
+		InitScripts:  true,
 	}
 }
 
-func (c Creator) Create(shell dbcreator.Shell, opts dbcreator.CreateOptions) error {
+func (c Creator) GetServiceDefinition(opts dbcreator.CreateOptions) (dbcreator.ServiceDefinition, error) {
+	def, _, _, err := newServiceDefinition(opts)
+	return def, err
+}
+
+// newServiceDefinition creates the service definition alongside with the
+// resolved init scripts and the setup steps, which are run by Create directly
+func newServiceDefinition(opts dbcreator.CreateOptions) (dbcreator.ServiceDefinition, []dbcreator.InitScript, []setupStep, error) {
+	// resolving scripts and escaping names beforehand, so we can fail early
+	scripts, err := dbcreator.ResolveInitScripts(opts.InitScripts, initScriptExtensions)
+	if err != nil {
+		return dbcreator.ServiceDefinition{}, nil, nil, err
+	}
+	steps, err := setupSteps(opts)
+	if err != nil {
+		return dbcreator.ServiceDefinition{}, nil, nil, err
+	}
+
 	// https://mcr.microsoft.com/product/mssql/server/about
-	args := []string{
-		"run", "-e", "ACCEPT_EULA=Y",
-		"--name", opts.ContainerName,
-		"--hostname", opts.ContainerName,
-		"-e", dbcreator.DockerEnv("MSSQL_SA_PASSWORD", opts.Password),
-	}
-	args = append(args, dbcreator.CreatePortBindingsArgument(port, opts.Ports)...)
-	args = append(args, "-d", fmt.Sprintf("mcr.microsoft.com/mssql/server:%s", opts.DockerTag))
-	shellOutput, err := shell.RunWithTeeOutput("docker", args...)
+	mounts, err := dbcreator.CreateVolumeMounts(opts.Volume, dataPath)
 	if err != nil {
-		return err
+		return dbcreator.ServiceDefinition{}, nil, nil, err
 	}
-	contID := strings.TrimSpace(shellOutput)
+	passwordEnv := dbcreator.DockerEnv("MSSQL_SA_PASSWORD", opts.Password)
+	def := dbcreator.ServiceDefinition{
+		Spec: dbcreator.ContainerSpec{
+			Name:         opts.ContainerName,
+			Hostname:     opts.ContainerName,
+			Image:        image,
+			Tag:          opts.DockerTag,
+			Env:          []string{"ACCEPT_EULA=Y", passwordEnv},
+			Labels:       dbcreator.CreateLabels("mssql", opts),
+			Port:         port,
+			PortBindings: opts.Ports,
+			Mounts:       mounts,
+		},
+		HealthCheck: append(sqlcmdArgs("localhost", opts.Password), "-b", "-Q", "SELECT 1"),
+		// the image has no init entrypoint, so the setup is performed by a
+		// sidecar with the same image, as it contains sqlcmd
+		Init: &dbcreator.ContainerSpec{
+			Name:       opts.ContainerName + "-init",
+			Image:      image,
+			Tag:        opts.DockerTag,
+			Env:        []string{passwordEnv},
+			Mounts:     dbcreator.CreateInitScriptsMounts(scripts),
+			Entrypoint: "bash",
+			Cmd:        []string{"-c", initContainerScript(opts.ContainerName, opts.Database, steps, scripts)},
+		},
+	}
+	return def, scripts, steps, nil
+}
+
+func (c Creator) Create(ctx context.Context, backend dbcreator.Backend, tx *dbcreator.Transaction, opts dbcreator.CreateOptions) (string, error) {
+	// init container isn't used, as scripts are run in the container directly
+	def, scripts, steps, err := newServiceDefinition(opts)
+	if err != nil {
+		return "", err
+	}
+
+	// with keep-id user namespace the server runs as the host user, which
+	// already owns the directory
+	source, isBindMount, _ := dbcreator.VolumeSource(opts.Volume)
+	if opts.Volume != "" && isBindMount && !backend.Runtime().KeepIDForBindMounts {
+		err := tx.Step("fixing data directory ownership", func() error {
+			return fixBindMountOwnership(ctx, backend, source, opts.DockerTag)
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+
+	contID, err := dbcreator.RunContainer(ctx, backend, tx, def.Spec)
+	if err != nil {
+		return contID, err
+	}
+
+	v := dbcreator.NewProgressLogger(ctx, opts.Verbose, opts.Quiet)
+	defer v.Done()
 
 	sql := SQLInContainerRunner{
-		shell:    &shell,
+		backend:  backend,
+		logger:   &v,
 		contID:   contID,
 		database: opts.Database,
 		password: opts.Password,
 		verbose:  opts.Verbose,
 	}
 
-	v := NewProgressLogger(opts.Verbose)
-	defer v.Done()
-	v.LogState("Waiting for db to be up and running...")
-
-	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
-	defer cancel()
-
 	// predelaying waiting for 1 seconds, as there's no way MsSQL can launch that
 	// fast, and connectivity timeouts take quite some time to resolve.
-	waitOpts := wait.Opts{PreDelay: 1000}
-
-	err = wait.For(ctx, func() error {
-		start := time.Now()
-		err := sql.RunSilent("SELECT SERVERPROPERTY('ProductVersion')")
-		end := time.Since(start)
-		v.LogVerbose("sql health check duration", end)
-		return err
-	}, waitOpts)
-	if err != nil {
-		return fmt.Errorf("failed to wait for the database to be operational: %w", err)
+	waitOpts := wait.Opts{PreDelay: time.Second}
+	probe := func(ctx context.Context) error {
+		return sql.RunSilent(ctx, "SELECT SERVERPROPERTY('ProductVersion')")
 	}
-
-	v.LogState("Creating the database and required data...")
-
-	escapedDBName, err := escapeID(opts.Database)
+	// Waiting regardless of opts.Wait, as we can't run any SQL before the db is up
+	err = tx.Step("waiting for the database", func() error {
+		return dbcreator.WaitForReady(ctx, backend, contID, opts.Timeout, &v, probe, waitOpts)
+	})
 	if err != nil {
-		return fmt.Errorf("error escaping the database name: %w", err)
+		return contID, err
 	}
 
-	err = sql.Run(fmt.Sprintf("CREATE DATABASE %s", escapedDBName))
-	if err != nil {
-		return err
+	for _, step := range steps {
+		v.LogState(step.state)
+		err = tx.Step(step.name, func() error {
+			if step.inDB {
+				return sql.RunInDB(ctx, step.sql)
+			}
+			return sql.Run(ctx, step.sql)
+		})
+		if err != nil {
+			return contID, err
+		}
 	}
 
-	v.LogState("Creating login")
-
-	escapedUser, err := escapeUser(opts.User)
+	err = tx.Step("running init scripts", func() error {
+		return runInitScripts(ctx, backend, sql, &v, scripts)
+	})
 	if err != nil {
-		return fmt.Errorf("error escaping the username: %w", err)
+		return contID, err
 	}
+	return contID, nil
+}
 
-	err = sql.Run(fmt.Sprintf("CREATE LOGIN %s WITH PASSWORD = %s", escapedUser, escapeStr(opts.Password)))
-	if err != nil {
-		return err
-	}
+// setupStep is a single SQL statement of the database setup, run as SA
+type setupStep struct {
+	// name of the transaction step
+	name string
+	// progress message
+	state string
+	sql   string
+	// run in the created database instead of master
+	inDB bool
+}
 
-	v.LogState("Creating user")
-	err = sql.RunInDB(fmt.Sprintf(`create user %s for login %s`, escapedUser, escapedUser))
+func setupSteps(opts dbcreator.CreateOptions) ([]setupStep, error) {
+	escapedDBName, err := escapeID(opts.Database)
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("error escaping the database name: %w", err)
 	}
-
-	// To check available roles: Select	[name] From sysusers Where issqlrole = 1
-	v.LogState("Adding required permissions")
-	err = sql.RunInDB(fmt.Sprintf("ALTER ROLE db_owner ADD MEMBER %s", escapedUser))
+	escapedUser, err := escapeUser(opts.User)
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("error escaping the username: %w", err)
 	}
-	return nil
+	return []setupStep{
+		{
+			name:  "creating the database",
+			state: "Creating the database and required data...",
+			sql:   fmt.Sprintf("CREATE DATABASE %s", escapedDBName),
+		},
+		{
+			name:  "creating the login",
+			state: "Creating login",
+			sql:   fmt.Sprintf("CREATE LOGIN %s WITH PASSWORD = %s", escapedUser, escapeStr(opts.Password)),
+		},
+		{
+			name:  "creating the user",
+			state: "Creating user",
+			sql:   fmt.Sprintf(`create user %s for login %s`, escapedUser, escapedUser),
+			inDB:  true,
+		},
+		// To check available roles: Select	[name] From sysusers Where issqlrole = 1
+		{
+			name:  "adding permissions",
+			state: "Adding required permissions",
+			sql:   fmt.Sprintf("ALTER ROLE db_owner ADD MEMBER %s", escapedUser),
+			inDB:  true,
+		},
+	}, nil
 }
 
 var (
-	ErrPasswordEmpty     error = errors.New("password can't be empty")
+	ErrPasswordEmpty     error = dbcreator.ErrPasswordEmpty
 	ErrPasswordTooShort  error = errors.New("password is too short (must be at least 10 chars)")
 	ErrPasswordTooSimple error = errors.New(
 		"password doesn't meet the complexity requirements " +
@@ -127,8 +219,8 @@ var (
 )
 
 func (c Creator) ValidatePassword(password string) error {
-	if password == "" {
-		return ErrPasswordEmpty
+	if err := dbcreator.CheckPassword(password); err != nil {
+		return err
 	}
 	if len(password) < 10 {
 		return ErrPasswordTooShort
@@ -0,0 +1,70 @@
+package mssql
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/religiosa1/init-docker-db/dbcreator"
+)
+
+func (c Creator) GetConnectionInfo(opts dbcreator.CreateOptions) dbcreator.ConnectionInfo {
+	info := dbcreator.NewConnectionInfo(opts, port)
+	uri := url.URL{
+		Scheme:   "sqlserver",
+		User:     url.UserPassword(info.User, info.Password),
+		Host:     info.Address(),
+		RawQuery: url.Values{"database": {info.Database}}.Encode(),
+	}
+	info.ConnectionStrings = []dbcreator.ConnectionString{
+		{Format: "uri", Value: uri.String()},
+		{Format: "ado", Value: makeADOConnectionString(info)},
+		{Format: "jdbc", Value: makeJDBCConnectionString(info)},
+	}
+	return info
+}
+
+// https://learn.microsoft.com/en-us/dotnet/api/system.data.sqlclient.sqlconnection.connectionstring
+func makeADOConnectionString(info dbcreator.ConnectionInfo) string {
+	// ADO.NET doesn't support IPv6 brackets notation with the port, so host
+	// and port are separated by a comma
+	return fmt.Sprintf(
+		"Server=%s,%d;Database=%s;User Id=%s;Password=%s;TrustServerCertificate=True",
+		escapeADOValue(info.Host), info.Port,
+		escapeADOValue(info.Database),
+		escapeADOValue(info.User),
+		escapeADOValue(info.Password),
+	)
+}
+
+// escapeADOValue wraps ADO.NET connection string value in quotes, if it
+// contains any of the special characters
+func escapeADOValue(value string) string {
+	if !strings.ContainsAny(value, ";'\"= ") {
+		return value
+	}
+	if !strings.ContainsRune(value, '"') {
+		return `"` + value + `"`
+	}
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+// https://learn.microsoft.com/en-us/sql/connect/jdbc/building-the-connection-url
+func makeJDBCConnectionString(info dbcreator.ConnectionInfo) string {
+	return fmt.Sprintf(
+		"jdbc:sqlserver://%s:%d;databaseName=%s;user=%s;password=%s;encrypt=true;trustServerCertificate=true",
+		info.Host, info.Port,
+		escapeJDBCValue(info.Database),
+		escapeJDBCValue(info.User),
+		escapeJDBCValue(info.Password),
+	)
+}
+
+// escapeJDBCValue wraps JDBC connection string value in braces, if it
+// contains any of the special characters
+func escapeJDBCValue(value string) string {
+	if !strings.ContainsAny(value, ";{}= ") {
+		return value
+	}
+	return "{" + strings.ReplaceAll(value, "}", "}}") + "}"
+}
@@ -0,0 +1,45 @@
+package mssql
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/religiosa1/init-docker-db/dbcreator"
+)
+
+// initContainerScript creates a bash script for the init sidecar, performing
+// the same setup as Create against the database service at host. The setup is
+// skipped if the database already exists, so restarting the sidecar is safe.
+// SA password is taken from MSSQL_SA_PASSWORD environment variable.
+func initContainerScript(host string, database string, steps []setupStep, scripts []dbcreator.InitScript) string {
+	sqlcmd := strings.Join(sqlcmdArgs(dbcreator.Quote(host), `"$MSSQL_SA_PASSWORD"`), " ")
+	existsQuery := "SET NOCOUNT ON; SELECT COUNT(*) FROM sys.databases WHERE name = " + escapeStr(database)
+
+	var sb strings.Builder
+	sb.WriteString("set -e\n")
+	fmt.Fprintf(&sb, "sqlcmd() { %s -b \"$@\"; }\n", sqlcmd)
+	fmt.Fprintf(&sb, "if [ \"$(sqlcmd -h -1 -W -Q %s)\" = 1 ]; then\n", dbcreator.Quote(existsQuery))
+	sb.WriteString("  echo 'Database already exists, skipping the setup'\n  exit 0\nfi\n")
+	for _, step := range steps {
+		if step.inDB {
+			fmt.Fprintf(&sb, "sqlcmd -d %s -Q %s\n", dbcreator.Quote(database), dbcreator.Quote(step.sql))
+		} else {
+			fmt.Fprintf(&sb, "sqlcmd -Q %s\n", dbcreator.Quote(step.sql))
+		}
+	}
+	for _, script := range scripts {
+		src := path.Join(dbcreator.InitScriptsDir, script.Name)
+		switch script.Ext() {
+		case ".sh":
+			fmt.Fprintf(&sb, "bash %s\n", dbcreator.Quote(src))
+		case ".sql.gz":
+			dst := path.Join(initScriptsContainerDir, strings.TrimSuffix(script.Name, ".gz"))
+			fmt.Fprintln(&sb, decompressCommand(src, dst))
+			fmt.Fprintf(&sb, "sqlcmd -d %s -i %s\n", dbcreator.Quote(database), dbcreator.Quote(dst))
+		default:
+			fmt.Fprintf(&sb, "sqlcmd -d %s -i %s\n", dbcreator.Quote(database), dbcreator.Quote(src))
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,32 @@
+package mssql
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/religiosa1/init-docker-db/dbcreator"
+)
+
+func TestInitContainerScript(t *testing.T) {
+	steps, err := setupSteps(dbcreator.CreateOptions{Database: "app db", User: "app", Password: "Pa$$word12"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	script := initContainerScript("sqldb", "app db", steps, []dbcreator.InitScript{
+		{Path: "/tmp/seed.sql.gz", Name: "01_seed.sql.gz"},
+		{Path: "/tmp/fixup.sh", Name: "02_fixup.sh"},
+	})
+
+	for _, want := range []string{
+		"-S sqldb -U SA -P \"$MSSQL_SA_PASSWORD\" -b",
+		"WHERE name = '\"'\"'app db'\"'\"''",
+		"sqlcmd -Q 'CREATE DATABASE [app db]'",
+		"sqlcmd -d 'app db' -Q 'ALTER ROLE db_owner ADD MEMBER [app]'",
+		"gzip -dc /docker-entrypoint-initdb.d/01_seed.sql.gz > /tmp/01_seed.sql\nsqlcmd -d 'app db' -i /tmp/01_seed.sql",
+		"bash /docker-entrypoint-initdb.d/02_fixup.sh",
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("Expected %q in the script, got\n%s", want, script)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package mssql
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/religiosa1/init-docker-db/dbcreator"
+)
+
+// Extensions of the supported init scripts. Unlike other images, MsSQL has no
+// init scripts entrypoint, so we're running them manually.
+var initScriptExtensions = []string{".sql", ".sql.gz", ".sh"}
+
+// directory inside of the container, where init scripts are copied to
+const initScriptsContainerDir = "/tmp"
+
+func runInitScripts(
+	ctx context.Context,
+	backend dbcreator.Backend,
+	sql SQLInContainerRunner,
+	logger *dbcreator.ProgressLogger,
+	scripts []dbcreator.InitScript,
+) error {
+	for _, script := range scripts {
+		logger.LogState(fmt.Sprintf("Running init script %s", path.Base(script.Path)))
+		if err := runInitScript(ctx, backend, sql, script); err != nil {
+			return fmt.Errorf("error running init script '%s': %w", script.Path, err)
+		}
+	}
+	return nil
+}
+
+func runInitScript(ctx context.Context, backend dbcreator.Backend, sql SQLInContainerRunner, script dbcreator.InitScript) error {
+	dst := path.Join(initScriptsContainerDir, script.Name)
+	if err := backend.CopyTo(ctx, sql.contID, script.Path, dst); err != nil {
+		return fmt.Errorf("error copying the script into the container: %w", err)
+	}
+
+	switch script.Ext() {
+	case ".sh":
+		out, err := backend.Exec(ctx, sql.contID, "bash", dst)
+		if err != nil {
+			return fmt.Errorf("%w\n%s", err, out)
+		}
+		return nil
+	case ".sql.gz":
+		src := dst
+		dst = strings.TrimSuffix(dst, ".gz")
+		out, err := backend.Exec(ctx, sql.contID, "sh", "-c", decompressCommand(src, dst))
+		if err != nil {
+			return fmt.Errorf("error decompressing the script: %w\n%s", err, out)
+		}
+	}
+	return sql.RunFile(ctx, dst)
+}
+
+// decompressCommand creates a shell command, decompressing gzipped script into
+// dst. gzip is available in the image, so decompressing inside of the
+// container; the source is kept, as the server's user may be unable to remove
+// it, e.g. if it's copied into /tmp by root.
+func decompressCommand(src string, dst string) string {
+	return fmt.Sprintf("gzip -dc %s > %s", dbcreator.Quote(src), dbcreator.Quote(dst))
+}
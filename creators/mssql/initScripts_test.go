@@ -0,0 +1,35 @@
+package mssql
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/religiosa1/init-docker-db/dbcreator"
+)
+
+func TestRunInitScripts(t *testing.T) {
+	var out bytes.Buffer
+	shell := dbcreator.NewShell(true, false).WithRuntime(dbcreator.Docker).WithOutput(&out)
+	backend := dbcreator.NewCLIBackend(shell)
+	ctx := context.Background()
+	logger := dbcreator.NewProgressLogger(ctx, false, true)
+	sql := SQLInContainerRunner{backend: backend, logger: &logger, contID: "sqldb", database: "db", password: "Pa$$word12"}
+
+	scripts := []dbcreator.InitScript{{Path: "/home/user/seed.sql.gz", Name: "01_seed.sql.gz"}}
+	if err := runInitScripts(ctx, backend, sql, &logger, scripts); err != nil {
+		t.Fatal(err)
+	}
+
+	script := out.String()
+	for _, want := range []string{
+		"docker cp /home/user/seed.sql.gz sqldb:/tmp/01_seed.sql.gz\n",
+		"docker exec sqldb sh -c 'gzip -dc /tmp/01_seed.sql.gz > /tmp/01_seed.sql'\n",
+		"-d db -i /tmp/01_seed.sql\n",
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("Expected %q in the script, got\n%s", want, script)
+		}
+	}
+}
@@ -2,6 +2,8 @@ package mssql
 
 import (
 	"testing"
+
+	"github.com/religiosa1/init-docker-db/dbcreator"
 )
 
 func TestValidatePassword(t *testing.T) {
@@ -14,6 +16,16 @@ func TestValidatePassword(t *testing.T) {
 		}
 	})
 
+	t.Run("generated password is ok", func(t *testing.T) {
+		password, err := dbcreator.GeneratePassword(creator.ValidatePassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := creator.ValidatePassword(password); err != nil {
+			t.Error(err)
+		}
+	})
+
 	t.Run("empty password", func(t *testing.T) {
 		err := creator.ValidatePassword("")
 		if err != ErrPasswordEmpty {
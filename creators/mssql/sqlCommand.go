@@ -1,6 +1,7 @@
 package mssql
 
 import (
+	"context"
 	"fmt"
 	"regexp"
 	"strings"
@@ -9,76 +10,98 @@ import (
 )
 
 type SQLInContainerRunner struct {
-	shell    *dbcreator.Shell
+	backend  dbcreator.Backend
+	logger   *dbcreator.ProgressLogger
 	contID   string
 	database string
 	password string
 	verbose  bool
 }
 
-func (r SQLInContainerRunner) RunSilent(sql string) error {
-	_, err := r.run(sql)
+func (r SQLInContainerRunner) RunSilent(ctx context.Context, sql string) error {
+	_, err := r.run(ctx, sql)
 	return err
 }
 
-func (r SQLInContainerRunner) Run(sql string) error {
-	out, err := r.run(sql)
+func (r SQLInContainerRunner) Run(ctx context.Context, sql string) error {
+	out, err := r.run(ctx, sql)
 	if err != nil && !r.verbose {
-		fmt.Println(out)
+		r.logger.Print(out)
 	}
 	return err
 }
 
-func (r SQLInContainerRunner) run(sql string) (string, error) {
-	if r.verbose {
-		if strings.ContainsRune(sql, '\n') {
-			fmt.Printf("SQL:\n%s --> END SQL \n", sql)
-		} else {
-			fmt.Printf("SQL: %s\n", sql)
-		}
+func (r SQLInContainerRunner) run(ctx context.Context, sql string) (string, error) {
+	if strings.ContainsRune(sql, '\n') {
+		r.logger.LogVerbose(fmt.Sprintf("SQL:\n%s --> END SQL ", sql))
+	} else {
+		r.logger.LogVerbose("SQL:", sql)
+	}
+	out, err := r.sqlcmd(ctx, "-Q", sql)
+	r.logger.LogVerbose(out)
+	// SQL errors are more descriptive, than the exit code
+	if sqlErr := parseSQLCommandError(out); sqlErr != nil {
+		return out, sqlErr
+	}
+	return out, err
+}
+
+// RunFile executes the SQL script file, located inside of the container, in
+// the runner's database
+func (r SQLInContainerRunner) RunFile(ctx context.Context, path string) error {
+	r.logger.LogVerbose("SQL file:", path)
+	out, err := r.sqlcmd(ctx, "-d", r.database, "-i", path)
+	r.logger.LogVerbose(out)
+	if sqlErr := parseSQLCommandError(out); sqlErr != nil {
+		err = sqlErr
 	}
+	if err != nil && !r.verbose {
+		r.logger.Print(out)
+	}
+	return err
+}
+
+func (r SQLInContainerRunner) sqlcmd(ctx context.Context, args ...string) (string, error) {
+	// -b makes sqlcmd exit with an error code on SQL errors, which is required
+	// for dry run scripts, as they can't parse the output
+	cmd := append(sqlcmdArgs("localhost", r.password), "-b")
+	return r.backend.Exec(ctx, r.contID, append(cmd, args...)...)
+}
+
+// sqlcmdArgs creates a sqlcmd command, connecting to the server as SA
+func sqlcmdArgs(server string, password string) []string {
 	// See https://github.com/microsoft/mssql-docker/issues/892
 	// Previous versions used mssql-tools, now it's mssql-tools18
-	out, err := r.shell.RunWithOutput(
-		"docker", "exec", r.contID,
-		"/opt/mssql-tools18/bin/sqlcmd", "-C", "-S", "localhost",
-		"-U", "SA", "-P", r.password, "-Q", sql,
-	)
-	if r.verbose {
-		fmt.Println(out)
-	}
-	if err != nil {
-		return out, err
+	return []string{
+		"/opt/mssql-tools18/bin/sqlcmd", "-C", "-S", server,
+		"-U", "SA", "-P", password,
 	}
-	return out, parseSQLCommandError(out)
 }
 
-func (r SQLInContainerRunner) RunInDB(sql string) error {
+func (r SQLInContainerRunner) RunInDB(ctx context.Context, sql string) error {
 	escapedDBName, err := escapeID(r.database)
 	if err != nil {
 		return err
 	}
-	return r.Run(fmt.Sprintf("use %s\n%s", escapedDBName, sql))
+	return r.Run(ctx, fmt.Sprintf("use %s\n%s", escapedDBName, sql))
 }
 
-var mssqlErrRe = regexp.MustCompile(`^Msg (?:\d+), Level (\d+), State (?:\d+), Server (?:[^,]+), Line (?:\d+)`)
+var mssqlErrRe = regexp.MustCompile(`(?m)^Msg (?:\d+), Level (\d+), State (?:\d+), Server (?:[^,]+)(?:, Procedure (?:[^,]+))?, Line (?:\d+)`)
 
 func parseSQLCommandError(output string) error {
-	matches := mssqlErrRe.FindStringSubmatch(output)
-	if matches == nil {
-		return nil
-	}
-
-	var errorLevel int
-	_, err := fmt.Sscanf(matches[1], "%d", &errorLevel)
-	if err != nil {
-		return err
-	}
+	// Scripts can produce multiple messages, so checking all of them
+	for _, matches := range mssqlErrRe.FindAllStringSubmatch(output, -1) {
+		var errorLevel int
+		_, err := fmt.Sscanf(matches[1], "%d", &errorLevel)
+		if err != nil {
+			return err
+		}
 
-	// Any severity level less or equal 10 we treat as not an error
-	// https://learn.microsoft.com/en-us/sql/relational-databases/errors-events/database-engine-error-severities?view=sql-server-ver16#levels-of-severity
-	if errorLevel > 10 {
-		return fmt.Errorf("sql error: %s", output)
+		// Any severity level less or equal 10 we treat as not an error
+		// https://learn.microsoft.com/en-us/sql/relational-databases/errors-events/database-engine-error-severities?view=sql-server-ver16#levels-of-severity
+		if errorLevel > 10 {
+			return fmt.Errorf("sql error: %s", output)
+		}
 	}
 	return nil
 }
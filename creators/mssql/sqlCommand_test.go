@@ -0,0 +1,43 @@
+package mssql
+
+import "testing"
+
+func Test_parseSQLCommandError(t *testing.T) {
+	cases := [...]struct {
+		name    string
+		output  string
+		isError bool
+	}{
+		{"empty output", "", false},
+		{"regular output", "(1 rows affected)\n", false},
+		{
+			"error at the start",
+			"Msg 1801, Level 16, State 3, Server foo, Line 1\nDatabase 'db' already exists.\n",
+			true,
+		},
+		{
+			"informational message",
+			"Msg 5701, Level 0, State 2, Server foo, Line 1\nChanged database context to 'db'.\n",
+			false,
+		},
+		{
+			"error after informational messages in a script",
+			"Changed database context to 'db'.\n(1 rows affected)\n" +
+				"Msg 208, Level 16, State 1, Server foo, Line 12\nInvalid object name 'bar'.\n",
+			true,
+		},
+		{
+			"error inside of a stored procedure",
+			"Msg 50000, Level 16, State 1, Server foo, Procedure bar, Line 3\nfailure\n",
+			true,
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			err := parseSQLCommandError(tt.output)
+			if (err != nil) != tt.isError {
+				t.Errorf("Unexpected result, want error: %v, got: %v", tt.isError, err)
+			}
+		})
+	}
+}
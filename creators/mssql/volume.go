@@ -0,0 +1,30 @@
+package mssql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/religiosa1/init-docker-db/dbcreator"
+)
+
+// Starting from 2019 images, MsSQL server runs as a non-root mssql user, so
+// it can't write into a bind mounted host directory owned by a different user.
+// https://learn.microsoft.com/en-us/sql/linux/sql-server-linux-docker-container-security#buildnonrootcontainer
+const mssqlUser = "10001:0"
+
+// fixBindMountOwnership changes the owner of a bind mounted host directory to
+// the mssql user, by running a disposable container as root
+func fixBindMountOwnership(ctx context.Context, backend dbcreator.Backend, source string, dockerTag string) error {
+	_, err := backend.RunOnce(ctx, dbcreator.ContainerSpec{
+		Image:      image,
+		Tag:        dockerTag,
+		User:       "root",
+		Mounts:     []dbcreator.Mount{{Source: source, Target: dataPath}},
+		Entrypoint: "chown",
+		Cmd:        []string{"-R", mssqlUser, dataPath},
+	})
+	if err != nil {
+		return fmt.Errorf("error changing data directory owner to the mssql user: %w", err)
+	}
+	return nil
+}
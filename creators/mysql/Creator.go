@@ -2,17 +2,38 @@
 package mysql
 
 import (
+	"context"
 	"fmt"
+	"net/url"
 
 	"github.com/religiosa1/init-docker-db/dbcreator"
+	"github.com/religiosa1/init-docker-db/wait"
 )
 
-type Creator struct{}
+type Creator struct {
+	// validate_password policy, the password is checked against
+	PasswordPolicy PasswordPolicy
+	// host pattern of the user, DefaultUserHost if empty
+	UserHost string
+	// authentication plugin of the users, image's default if empty
+	AuthPlugin string
+	// generate the root password by the image instead of using the user's one
+	RandomRootPassword bool
+}
+
+const (
+	port     uint16 = 3306
+	image           = "mysql"
+	dataPath        = "/var/lib/mysql"
+)
 
-const port uint16 = 3306
+// Extensions of the init scripts, supported by the image's entrypoint
+var initScriptExtensions = []string{".sh", ".sql", ".sql.gz", ".sql.bz2", ".sql.xz", ".sql.zst"}
 
 func (c Creator) GetDefaultOpts() dbcreator.DefaultOpts {
 	return dbcreator.DefaultOpts{
+		Image:     image,
+		DataPath:  dataPath,
 		Port:      port,
 		User:      "mysql",
 		DockerTag: "lts",
@@ -24,23 +45,83 @@ func (c Creator) GetCapabilities() dbcreator.Capabilities {
 	return dbcreator.Capabilities{
 		DatabaseName: true,
 		UserPassword: true,
+		RootPassword: true,
+		InitScripts:  true,
 	}
 }
 
-func (c Creator) Create(shell dbcreator.Shell, opts dbcreator.CreateOptions) error {
+func (c Creator) GetServiceDefinition(opts dbcreator.CreateOptions) (dbcreator.ServiceDefinition, error) {
 	// https://hub.docker.com/_/mysql
-	args := []string{
-		"run", "--name", opts.ContainerName,
-		"-e", dbcreator.DockerEnv("MYSQL_USER", opts.User),
-		"-e", dbcreator.DockerEnv("MYSQL_ROOT_PASSWORD", opts.Password),
-		"-e", dbcreator.DockerEnv("MYSQL_PASSWORD", opts.Password),
-		"-e", dbcreator.DockerEnv("MYSQL_DATABASE", opts.Database),
-	}
-	args = append(args, dbcreator.CreatePortBindingsArgument(port, opts.Ports)...)
-	args = append(args, "-d", fmt.Sprintf("mysql:%s", opts.DockerTag))
-	return shell.Run("docker", args...)
+	mounts, err := dbcreator.CreateVolumeMounts(opts.Volume, dataPath)
+	if err != nil {
+		return dbcreator.ServiceDefinition{}, err
+	}
+	scripts, err := dbcreator.ResolveInitScripts(opts.InitScripts, initScriptExtensions)
+	if err != nil {
+		return dbcreator.ServiceDefinition{}, err
+	}
+	env, err := c.userEnv(opts)
+	if err != nil {
+		return dbcreator.ServiceDefinition{}, err
+	}
+	args, err := c.serverArgs(opts.DockerTag)
+	if err != nil {
+		return dbcreator.ServiceDefinition{}, err
+	}
+	def := dbcreator.ServiceDefinition{
+		Spec: dbcreator.ContainerSpec{
+			Name:         opts.ContainerName,
+			Image:        image,
+			Tag:          opts.DockerTag,
+			Env:          append(env, dbcreator.DockerEnv("MYSQL_DATABASE", opts.Database)),
+			Labels:       dbcreator.CreateLabels("mysql", opts),
+			Port:         port,
+			PortBindings: opts.Ports,
+			Mounts:       append(mounts, dbcreator.CreateInitScriptsMounts(scripts)...),
+		},
+		// connecting through TCP, as the entrypoint starts a temporary server
+		// with networking disabled during the initialization
+		HealthCheck: []string{"mysqladmin", "ping", "-h", "127.0.0.1", "--protocol=tcp", "--silent"},
+	}
+	if c.customUser(opts) {
+		def.Spec.Entrypoint = "bash"
+		def.Spec.Cmd = []string{"-c", userEntrypoint(args)}
+	} else if len(args) > 0 {
+		def.Spec.Cmd = append([]string{"mysqld"}, args...)
+	}
+	return def, nil
+}
+
+func (c Creator) Create(ctx context.Context, backend dbcreator.Backend, tx *dbcreator.Transaction, opts dbcreator.CreateOptions) (string, error) {
+	def, err := c.GetServiceDefinition(opts)
+	if err != nil {
+		return "", err
+	}
+	contID, err := dbcreator.RunContainer(ctx, backend, tx, def.Spec)
+	if err != nil || !opts.Wait {
+		return contID, err
+	}
+
+	logger := dbcreator.NewProgressLogger(ctx, opts.Verbose, opts.Quiet)
+	defer logger.Done()
+	probe := dbcreator.ExecProbe(backend, contID, def.HealthCheck...)
+	return contID, tx.Step("waiting for the database", func() error {
+		return dbcreator.WaitForReady(ctx, backend, contID, opts.Timeout, &logger, probe, wait.Opts{})
+	})
 }
 
-func (c Creator) ValidatePassword(password string) error {
-	return nil
+func (c Creator) GetConnectionInfo(opts dbcreator.CreateOptions) dbcreator.ConnectionInfo {
+	info := dbcreator.NewConnectionInfo(opts, port)
+	uri := url.URL{
+		Scheme: "mysql",
+		User:   url.UserPassword(info.User, info.Password),
+		Host:   info.Address(),
+		Path:   "/" + info.Database,
+	}
+	info.ConnectionStrings = []dbcreator.ConnectionString{
+		{Format: "uri", Value: uri.String()},
+		// https://github.com/go-sql-driver/mysql#dsn-data-source-name
+		{Format: "dsn", Value: fmt.Sprintf("%s:%s@tcp(%s)/%s", info.User, info.Password, info.Address(), info.Database)},
+	}
+	return info
 }
@@ -0,0 +1,84 @@
+package mysql
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/religiosa1/init-docker-db/dbcreator"
+)
+
+// PasswordPolicy is a level of the validate_password component policy, the
+// password is checked against, if the server has the component enabled
+// https://dev.mysql.com/doc/refman/8.4/en/validate-password-options-variables.html#sysvar_validate_password.policy
+type PasswordPolicy string
+
+const (
+	// no policy, only the checks common to all of the databases
+	PasswordPolicyNone   PasswordPolicy = ""
+	PasswordPolicyLow    PasswordPolicy = "low"
+	PasswordPolicyMedium PasswordPolicy = "medium"
+	// same as medium, as the dictionary file isn't configured by default
+	PasswordPolicyStrong PasswordPolicy = "strong"
+)
+
+// validate_password component defaults
+const (
+	minPasswordLength = 8
+	minMixedCaseCount = 1
+	minNumberCount    = 1
+	minSpecialCount   = 1
+)
+
+var (
+	ErrPasswordTooShort  error = fmt.Errorf("password is too short (must be at least %d chars)", minPasswordLength)
+	ErrPasswordTooSimple error = errors.New(
+		"password doesn't meet the complexity requirements " +
+			"(must contain a lowercase char, an uppercase char, a digit and a non-alphanumeric char)",
+	)
+)
+
+// ParsePasswordPolicy parses the case-insensitive policy name
+func ParsePasswordPolicy(name string) (PasswordPolicy, error) {
+	policy := PasswordPolicy(strings.ToLower(name))
+	switch policy {
+	case PasswordPolicyNone, PasswordPolicyLow, PasswordPolicyMedium, PasswordPolicyStrong:
+		return policy, nil
+	}
+	return "", fmt.Errorf("unknown password policy '%s', must be one of: low, medium, strong", name)
+}
+
+func (c Creator) ValidatePassword(password string) error {
+	if err := dbcreator.CheckPassword(password); err != nil {
+		return err
+	}
+	if c.PasswordPolicy == PasswordPolicyNone {
+		return nil
+	}
+	// the component counts characters, not bytes
+	if len([]rune(password)) < minPasswordLength {
+		return ErrPasswordTooShort
+	}
+	if c.PasswordPolicy == PasswordPolicyLow {
+		return nil
+	}
+
+	var lower, upper, digits, special int
+	for _, c := range password {
+		switch {
+		case unicode.IsLower(c):
+			lower++
+		case unicode.IsUpper(c):
+			upper++
+		case unicode.IsDigit(c):
+			digits++
+		case !unicode.IsLetter(c):
+			special++
+		}
+	}
+	if lower < minMixedCaseCount || upper < minMixedCaseCount || digits < minNumberCount || special < minSpecialCount {
+		return ErrPasswordTooSimple
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package mysql
+
+import (
+	"testing"
+
+	"github.com/religiosa1/init-docker-db/dbcreator"
+)
+
+func TestValidatePassword(t *testing.T) {
+	t.Run("generated password is ok", func(t *testing.T) {
+		creator := Creator{PasswordPolicy: PasswordPolicyStrong}
+		password, err := dbcreator.GeneratePassword(creator.ValidatePassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := creator.ValidatePassword(password); err != nil {
+			t.Error(err)
+		}
+	})
+
+	tests := []struct {
+		name   string
+		policy PasswordPolicy
+		input  string
+		output error
+	}{
+		{"empty password", PasswordPolicyNone, "", dbcreator.ErrPasswordEmpty},
+		{"new line", PasswordPolicyNone, "pass\nword", dbcreator.ErrPasswordControlChars},
+		{"no policy", PasswordPolicyNone, "123", nil},
+		{"low too short", PasswordPolicyLow, "1234567", ErrPasswordTooShort},
+		{"low", PasswordPolicyLow, "12345678", nil},
+		{"multibyte chars are counted as one", PasswordPolicyLow, "пароль1", ErrPasswordTooShort},
+		{"medium too short", PasswordPolicyMedium, "Pa$s1", ErrPasswordTooShort},
+		{"medium", PasswordPolicyMedium, "Passw0rd!", nil},
+		{"medium no upper case", PasswordPolicyMedium, "passw0rd!", ErrPasswordTooSimple},
+		{"medium no lower case", PasswordPolicyMedium, "PASSW0RD!", ErrPasswordTooSimple},
+		{"medium no digits", PasswordPolicyMedium, "Password!", ErrPasswordTooSimple},
+		{"medium no special chars", PasswordPolicyMedium, "Passw0rd1", ErrPasswordTooSimple},
+		{"strong", PasswordPolicyStrong, "Passw0rd!", nil},
+		{"strong no special chars", PasswordPolicyStrong, "Passw0rd1", ErrPasswordTooSimple},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			creator := Creator{PasswordPolicy: tt.policy}
+			err := creator.ValidatePassword(tt.input)
+			if err != tt.output {
+				t.Errorf("Want '%s', got: '%s'", tt.output, err)
+			}
+		})
+	}
+}
+
+func TestParsePasswordPolicy(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    PasswordPolicy
+		wantErr bool
+	}{
+		{"", PasswordPolicyNone, false},
+		{"low", PasswordPolicyLow, false},
+		{"MEDIUM", PasswordPolicyMedium, false},
+		{"Strong", PasswordPolicyStrong, false},
+		{"high", PasswordPolicyNone, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParsePasswordPolicy(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Unexpected error value: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Want '%s', got: '%s'", tt.want, got)
+			}
+		})
+	}
+}
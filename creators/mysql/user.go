@@ -0,0 +1,150 @@
+package mysql
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/religiosa1/init-docker-db/dbcreator"
+)
+
+// Authentication plugins of the created users
+const (
+	AuthPluginCachingSHA2 = "caching_sha2_password"
+	// legacy plugin, for the drivers without caching_sha2_password support;
+	// it's removed in MySQL 9
+	AuthPluginNative = "mysql_native_password"
+)
+
+// DefaultUserHost is the host pattern of the user, allowing connections from
+// any host; connections from the docker host aren't coming from localhost
+const DefaultUserHost = "%"
+
+const rootUser = "root"
+
+var (
+	ErrNativePasswordUnsupported error = errors.New("mysql_native_password plugin isn't supported by MySQL 9, use an 8.x --tag, e.g. 8.4")
+	ErrNativePasswordUnknownTag  error = errors.New("MySQL version of the --tag is unknown, so mysql_native_password plugin support can't be checked; use an 8.x --tag, e.g. 8.4")
+	ErrRandomRootPassword        error = errors.New("random root password can't be used with a separate root password or the root user")
+)
+
+// userScriptPath is the init script, which creates the user instead of the
+// image, as MYSQL_USER is always created with '%' host pattern. Its name is
+// sorted before the seed scripts.
+const userScriptPath = dbcreator.InitScriptsDir + "/000-init-docker-db-user.sh"
+
+// userScript is sourced by the image's entrypoint, so it uses its helpers to
+// run SQL as root and to escape the values, taken from the environment
+const userScript = `user="'$(docker_sql_escape_string_literal "$MYSQL_APP_USER")'@'$(docker_sql_escape_string_literal "$MYSQL_APP_HOST")'"
+mysql_note "Creating user $user"
+docker_process_sql --database=mysql <<EOSQL
+CREATE USER $user IDENTIFIED BY '$(docker_sql_escape_string_literal "$MYSQL_APP_PASSWORD")';
+` + "GRANT ALL ON \\`${MYSQL_DATABASE//_/\\\\_}\\`.* TO $user;\n" + `EOSQL
+`
+
+// userEnv returns the image's environment variables, creating the user and
+// setting the root password. The root user itself is configured through the
+// root variables only, as the image refuses MYSQL_USER=root. Without a
+// separate root password the user's one is used, unless a random one is
+// requested, which the image prints to the container logs.
+func (c Creator) userEnv(opts dbcreator.CreateOptions) ([]string, error) {
+	host := c.userHost()
+	if c.RandomRootPassword && (opts.User == rootUser || opts.RootPassword != "") {
+		return nil, ErrRandomRootPassword
+	}
+	if opts.User == rootUser {
+		if opts.RootPassword != "" && opts.RootPassword != opts.Password {
+			return nil, dbcreator.ErrRootPasswordWithRootUser
+		}
+		return []string{
+			dbcreator.DockerEnv("MYSQL_ROOT_PASSWORD", opts.Password),
+			dbcreator.DockerEnv("MYSQL_ROOT_HOST", host),
+		}, nil
+	}
+
+	var env []string
+	switch {
+	case c.RandomRootPassword:
+		env = append(env, dbcreator.DockerEnv("MYSQL_RANDOM_ROOT_PASSWORD", "yes"))
+	case opts.RootPassword != "":
+		env = append(env, dbcreator.DockerEnv("MYSQL_ROOT_PASSWORD", opts.RootPassword))
+	default:
+		env = append(env, dbcreator.DockerEnv("MYSQL_ROOT_PASSWORD", opts.Password))
+	}
+	if host == DefaultUserHost {
+		return append(env,
+			dbcreator.DockerEnv("MYSQL_USER", opts.User),
+			dbcreator.DockerEnv("MYSQL_PASSWORD", opts.Password),
+		), nil
+	}
+	return append(env,
+		dbcreator.DockerEnv("MYSQL_APP_USER", opts.User),
+		dbcreator.DockerEnv("MYSQL_APP_PASSWORD", opts.Password),
+		dbcreator.DockerEnv("MYSQL_APP_HOST", host),
+	), nil
+}
+
+func (c Creator) userHost() string {
+	if c.UserHost == "" {
+		return DefaultUserHost
+	}
+	return c.UserHost
+}
+
+// serverArgs returns mysqld arguments, making the auth plugin the default one
+// for the created users
+func (c Creator) serverArgs(tag string) ([]string, error) {
+	if c.AuthPlugin != AuthPluginNative {
+		return nil, nil
+	}
+	if err := checkNativePassword(tag); err != nil {
+		return nil, err
+	}
+	// the plugin is disabled by default since 8.4, and the option is unknown
+	// for the earlier versions, hence the loose prefix
+	return []string{"--loose-mysql-native-password=ON", "--authentication-policy=" + AuthPluginNative}, nil
+}
+
+// versionTagPattern matches the major version of the tags, e.g. "8.4" or
+// "8-oracle"
+var versionTagPattern = regexp.MustCompile(`^(\d+)(?:[.-]|$)`)
+
+// checkNativePassword checks, that the image of the tag supports the native
+// password plugin. Tags without a version, e.g. "oracle" or digests, can
+// refer to 9.x images, so they're rejected, except for the lts ones (8.4).
+func checkNativePassword(tag string) error {
+	if tag == "lts" || strings.HasPrefix(tag, "lts-") {
+		return nil
+	}
+	if tag == "latest" || strings.HasPrefix(tag, "innovation") {
+		return ErrNativePasswordUnsupported
+	}
+	match := versionTagPattern.FindStringSubmatch(tag)
+	if match == nil {
+		return ErrNativePasswordUnknownTag
+	}
+	if major, err := strconv.Atoi(match[1]); err != nil || major >= 9 {
+		return ErrNativePasswordUnsupported
+	}
+	return nil
+}
+
+// customUser reports whether the user is created by the init script
+func (c Creator) customUser(opts dbcreator.CreateOptions) bool {
+	return opts.User != rootUser && c.userHost() != DefaultUserHost
+}
+
+// userEntrypoint creates a bash script, writing the user init script and
+// starting the server with the arguments through the image's entrypoint
+func userEntrypoint(args []string) string {
+	var sb strings.Builder
+	sb.WriteString("set -e\n")
+	sb.WriteString("cat > " + userScriptPath + " <<'EOF'\n" + userScript + "EOF\n")
+	sb.WriteString("exec docker-entrypoint.sh mysqld")
+	for _, arg := range args {
+		sb.WriteString(" " + dbcreator.Quote(arg))
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
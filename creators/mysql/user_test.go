@@ -0,0 +1,155 @@
+package mysql
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/religiosa1/init-docker-db/dbcreator"
+)
+
+func TestUserEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		creator Creator
+		opts    dbcreator.CreateOptions
+		want    []string
+		err     error
+	}{
+		{
+			name: "user's root password",
+			opts: dbcreator.CreateOptions{User: "app", Password: "pass"},
+			want: []string{"MYSQL_ROOT_PASSWORD=pass", "MYSQL_USER=app", "MYSQL_PASSWORD=pass"},
+		},
+		{
+			name:    "random root password",
+			creator: Creator{RandomRootPassword: true},
+			opts:    dbcreator.CreateOptions{User: "app", Password: "pass"},
+			want:    []string{"MYSQL_RANDOM_ROOT_PASSWORD=yes", "MYSQL_USER=app", "MYSQL_PASSWORD=pass"},
+		},
+		{
+			name:    "random and separate root password",
+			creator: Creator{RandomRootPassword: true},
+			opts:    dbcreator.CreateOptions{User: "app", Password: "pass", RootPassword: "root-pass"},
+			err:     ErrRandomRootPassword,
+		},
+		{
+			name:    "random root password with root user",
+			creator: Creator{RandomRootPassword: true},
+			opts:    dbcreator.CreateOptions{User: "root", Password: "pass"},
+			err:     ErrRandomRootPassword,
+		},
+		{
+			name: "separate root password",
+			opts: dbcreator.CreateOptions{User: "app", Password: "pass", RootPassword: "root-pass"},
+			want: []string{"MYSQL_ROOT_PASSWORD=root-pass", "MYSQL_USER=app", "MYSQL_PASSWORD=pass"},
+		},
+		{
+			name:    "custom host",
+			creator: Creator{UserHost: "localhost"},
+			opts:    dbcreator.CreateOptions{User: "app", Password: "pass"},
+			want:    []string{"MYSQL_ROOT_PASSWORD=pass", "MYSQL_APP_USER=app", "MYSQL_APP_PASSWORD=pass", "MYSQL_APP_HOST=localhost"},
+		},
+		{
+			name:    "root user",
+			creator: Creator{UserHost: "localhost"},
+			opts:    dbcreator.CreateOptions{User: "root", Password: "pass"},
+			want:    []string{"MYSQL_ROOT_PASSWORD=pass", "MYSQL_ROOT_HOST=localhost"},
+		},
+		{
+			name: "root user with the same root password",
+			opts: dbcreator.CreateOptions{User: "root", Password: "pass", RootPassword: "pass"},
+			want: []string{"MYSQL_ROOT_PASSWORD=pass", "MYSQL_ROOT_HOST=%"},
+		},
+		{
+			name: "root user with a different root password",
+			opts: dbcreator.CreateOptions{User: "root", Password: "pass", RootPassword: "root-pass"},
+			err:  dbcreator.ErrRootPasswordWithRootUser,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.creator.userEnv(tt.opts)
+			if err != tt.err {
+				t.Fatalf("Want '%v', got: '%v'", tt.err, err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Want %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestServiceDefinitionCommand(t *testing.T) {
+	tests := []struct {
+		name       string
+		creator    Creator
+		tag        string
+		entrypoint string
+		cmd        string
+		err        error
+	}{
+		{name: "defaults", tag: "lts"},
+		{
+			name:    "native password",
+			creator: Creator{AuthPlugin: AuthPluginNative},
+			tag:     "8.0-debian",
+			cmd:     "mysqld --loose-mysql-native-password=ON --authentication-policy=mysql_native_password",
+		},
+		{
+			name:       "custom host",
+			creator:    Creator{UserHost: "localhost", AuthPlugin: AuthPluginCachingSHA2},
+			tag:        "8.4",
+			entrypoint: "bash",
+			cmd:        "cat > " + userScriptPath + " <<'EOF'\n" + userScript + "EOF\nexec docker-entrypoint.sh mysqld\n",
+		},
+		{name: "native password in MySQL 9", creator: Creator{AuthPlugin: AuthPluginNative}, tag: "9.1", err: ErrNativePasswordUnsupported},
+		{name: "native password in latest", creator: Creator{AuthPlugin: AuthPluginNative}, tag: "latest", err: ErrNativePasswordUnsupported},
+		{name: "native password in unversioned tag", creator: Creator{AuthPlugin: AuthPluginNative}, tag: "oracle", err: ErrNativePasswordUnknownTag},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := dbcreator.CreateOptions{User: "app", Password: "pass", Database: "db", DockerTag: tt.tag}
+			def, err := tt.creator.GetServiceDefinition(opts)
+			if err != tt.err {
+				t.Fatalf("Want '%v', got: '%v'", tt.err, err)
+			}
+			if def.Spec.Entrypoint != tt.entrypoint {
+				t.Errorf("Want entrypoint %q, got %q", tt.entrypoint, def.Spec.Entrypoint)
+			}
+			if cmd := strings.Join(def.Spec.Cmd, " "); !strings.HasSuffix(cmd, tt.cmd) {
+				t.Errorf("Expected command to end with %q, got %q", tt.cmd, cmd)
+			}
+		})
+	}
+}
+
+func TestCheckNativePassword(t *testing.T) {
+	tests := []struct {
+		tag string
+		err error
+	}{
+		{"8.0", nil},
+		{"8.4.3-oraclelinux9", nil},
+		{"8-oracle", nil},
+		{"8", nil},
+		{"lts", nil},
+		{"lts-oracle", nil},
+		{"9.1", ErrNativePasswordUnsupported},
+		{"9-oracle", ErrNativePasswordUnsupported},
+		{"10.0", ErrNativePasswordUnsupported},
+		{"latest", ErrNativePasswordUnsupported},
+		{"innovation-oracle", ErrNativePasswordUnsupported},
+		{"oracle", ErrNativePasswordUnknownTag},
+		{"oraclelinux9", ErrNativePasswordUnknownTag},
+		{"sha256:4b3a2c", ErrNativePasswordUnknownTag},
+		{"", ErrNativePasswordUnknownTag},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			if err := checkNativePassword(tt.tag); err != tt.err {
+				t.Errorf("Want '%v', got: '%v'", tt.err, err)
+			}
+		})
+	}
+}
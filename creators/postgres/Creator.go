@@ -2,17 +2,29 @@
 package postgres
 
 import (
+	"context"
 	"fmt"
+	"net/url"
 
 	"github.com/religiosa1/init-docker-db/dbcreator"
+	"github.com/religiosa1/init-docker-db/wait"
 )
 
 type Creator struct{}
 
-const port uint16 = 5432
+const (
+	port     uint16 = 5432
+	image           = "postgres"
+	dataPath        = "/var/lib/postgresql/data"
+)
+
+// Extensions of the init scripts, supported by the image's entrypoint
+var initScriptExtensions = []string{".sh", ".sql", ".sql.gz", ".sql.xz", ".sql.zst"}
 
 func (c Creator) GetDefaultOpts() dbcreator.DefaultOpts {
 	return dbcreator.DefaultOpts{
+		Image:     image,
+		DataPath:  dataPath,
 		Port:      port,
 		User:      "postgres",
 		DockerTag: "latest",
@@ -24,22 +36,86 @@ func (c Creator) GetCapabilities() dbcreator.Capabilities {
 	return dbcreator.Capabilities{
 		DatabaseName: true,
 		UserPassword: true,
+		InitScripts:  true,
 	}
 }
 
-func (c Creator) Create(shell dbcreator.Shell, opts dbcreator.CreateOptions) error {
+func (c Creator) GetServiceDefinition(opts dbcreator.CreateOptions) (dbcreator.ServiceDefinition, error) {
 	// https://hub.docker.com/_/postgres
-	args := []string{
-		"run", "--name", opts.ContainerName,
-		"-e", dbcreator.DockerEnv("POSTGRES_PASSWORD", opts.Password),
-		"-e", dbcreator.DockerEnv("POSTGRES_USER", opts.User),
-		"-e", dbcreator.DockerEnv("POSTGRES_DB", opts.Database),
-	}
-	args = append(args, dbcreator.CreatePortBindingsArgument(port, opts.Ports)...)
-	args = append(args, "-d", fmt.Sprintf("postgres:%s", opts.DockerTag))
-	return shell.Run("docker", args...)
+	mounts, err := dbcreator.CreateVolumeMounts(opts.Volume, dataPath)
+	if err != nil {
+		return dbcreator.ServiceDefinition{}, err
+	}
+	scripts, err := dbcreator.ResolveInitScripts(opts.InitScripts, initScriptExtensions)
+	if err != nil {
+		return dbcreator.ServiceDefinition{}, err
+	}
+	return dbcreator.ServiceDefinition{
+		Spec: dbcreator.ContainerSpec{
+			Name:  opts.ContainerName,
+			Image: image,
+			Tag:   opts.DockerTag,
+			Env: []string{
+				dbcreator.DockerEnv("POSTGRES_PASSWORD", opts.Password),
+				dbcreator.DockerEnv("POSTGRES_USER", opts.User),
+				dbcreator.DockerEnv("POSTGRES_DB", opts.Database),
+			},
+			Labels:       dbcreator.CreateLabels("postgres", opts),
+			Port:         port,
+			PortBindings: opts.Ports,
+			Mounts:       append(mounts, dbcreator.CreateInitScriptsMounts(scripts)...),
+		},
+		// connecting through TCP, as the entrypoint starts a temporary server
+		// listening only on the unix socket during the initialization
+		HealthCheck: []string{"pg_isready", "-h", "127.0.0.1", "-U", opts.User, "-d", opts.Database},
+	}, nil
+}
+
+func (c Creator) Create(ctx context.Context, backend dbcreator.Backend, tx *dbcreator.Transaction, opts dbcreator.CreateOptions) (string, error) {
+	def, err := c.GetServiceDefinition(opts)
+	if err != nil {
+		return "", err
+	}
+	contID, err := dbcreator.RunContainer(ctx, backend, tx, def.Spec)
+	if err != nil || !opts.Wait {
+		return contID, err
+	}
+
+	logger := dbcreator.NewProgressLogger(ctx, opts.Verbose, opts.Quiet)
+	defer logger.Done()
+	probe := dbcreator.ExecProbe(backend, contID, def.HealthCheck...)
+	return contID, tx.Step("waiting for the database", func() error {
+		return dbcreator.WaitForReady(ctx, backend, contID, opts.Timeout, &logger, probe, wait.Opts{})
+	})
 }
 
+// psql can't use 100+ characters passwords, passed through PGPASSWORD, which
+// the image warns about
+// https://github.com/docker-library/postgres/issues/507
+const maxPasswordLength = 99
+
+var ErrPasswordTooLong error = fmt.Errorf("password is too long (must be at most %d chars)", maxPasswordLength)
+
 func (c Creator) ValidatePassword(password string) error {
+	if err := dbcreator.CheckPassword(password); err != nil {
+		return err
+	}
+	if len(password) > maxPasswordLength {
+		return ErrPasswordTooLong
+	}
 	return nil
 }
+
+func (c Creator) GetConnectionInfo(opts dbcreator.CreateOptions) dbcreator.ConnectionInfo {
+	info := dbcreator.NewConnectionInfo(opts, port)
+	uri := url.URL{
+		Scheme: "postgres",
+		User:   url.UserPassword(info.User, info.Password),
+		Host:   info.Address(),
+		Path:   "/" + info.Database,
+	}
+	info.ConnectionStrings = []dbcreator.ConnectionString{
+		{Format: "uri", Value: uri.String()},
+	}
+	return info
+}
@@ -0,0 +1,40 @@
+package postgres
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/religiosa1/init-docker-db/dbcreator"
+)
+
+func TestValidatePassword(t *testing.T) {
+	creator := Creator{}
+
+	t.Run("default password is ok", func(t *testing.T) {
+		defaultOpts := creator.GetDefaultOpts()
+		if err := creator.ValidatePassword(defaultOpts.Password); err != nil {
+			t.Error(err)
+		}
+	})
+
+	tests := []struct {
+		name   string
+		input  string
+		output error
+	}{
+		{"empty password", "", dbcreator.ErrPasswordEmpty},
+		{"new line", "pass\nword", dbcreator.ErrPasswordControlChars},
+		{"tab", "pass\tword", dbcreator.ErrPasswordControlChars},
+		{"special chars", `pa$$ 'word"`, nil},
+		{"max length", strings.Repeat("a", maxPasswordLength), nil},
+		{"too long", strings.Repeat("a", maxPasswordLength+1), ErrPasswordTooLong},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := creator.ValidatePassword(tt.input)
+			if err != tt.output {
+				t.Errorf("Want '%s', got: '%s'", tt.output, err)
+			}
+		})
+	}
+}
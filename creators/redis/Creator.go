@@ -2,17 +2,42 @@
 package redis
 
 import (
-	"fmt"
+	"context"
+	"errors"
+	"net/url"
 
 	"github.com/religiosa1/init-docker-db/dbcreator"
+	"github.com/religiosa1/init-docker-db/wait"
 )
 
-type Creator struct{}
+type Creator struct {
+	// ACL rules of the created user, DefaultACLRules if empty
+	ACLRules string
+	// one of the Persistence modes, RDB snapshots (or the config file's
+	// setting) if empty
+	Persistence string
+	// RDB snapshot intervals in "seconds changes ..." format, DefaultSave if empty
+	Save string
+	// AOF fsync policy: always, everysec or no
+	AppendFsync string
+	// memory limit, e.g. "100mb"
+	MaxMemory string
+	// eviction policy, applied when the memory limit is reached
+	MaxMemoryPolicy string
+	// absolute host path of redis.conf to run the server with
+	ConfigFile string
+}
 
-const port uint16 = 6379
+const (
+	port     uint16 = 6379
+	image           = "redis"
+	dataPath        = "/data"
+)
 
 func (c Creator) GetDefaultOpts() dbcreator.DefaultOpts {
 	return dbcreator.DefaultOpts{
+		Image:     image,
+		DataPath:  dataPath,
 		Port:      port,
 		User:      "",
 		DockerTag: "latest",
@@ -24,19 +49,96 @@ func (c Creator) GetCapabilities() dbcreator.Capabilities {
 	return dbcreator.Capabilities{
 		DatabaseName: false,
 		UserPassword: false,
+		PasswordOnly: true,
 	}
 }
 
-func (c Creator) Create(shell dbcreator.Shell, opts dbcreator.CreateOptions) error {
+func (c Creator) GetServiceDefinition(opts dbcreator.CreateOptions) (dbcreator.ServiceDefinition, error) {
 	// https://hub.docker.com/_/redis/
-	args := []string{"run", "--name", opts.ContainerName}
-	args = append(args, dbcreator.CreatePortBindingsArgument(port, opts.Ports)...)
-	args = append(args, "-d", fmt.Sprintf("redis:%s", opts.DockerTag),
-		"redis-server", "--save", "60", "1", "--loglevel", "warning")
+	if opts.User != "" && opts.Password == "" {
+		return dbcreator.ServiceDefinition{}, ErrUserWithoutPassword
+	}
+	if c.ACLRules != "" && isDefaultUser(opts.User) {
+		return dbcreator.ServiceDefinition{}, ErrACLWithoutUser
+	}
+	if err := c.checkACL(opts); err != nil {
+		return dbcreator.ServiceDefinition{}, err
+	}
+	mounts, err := dbcreator.CreateVolumeMounts(opts.Volume, dataPath)
+	if err != nil {
+		return dbcreator.ServiceDefinition{}, err
+	}
+	if c.ConfigFile != "" {
+		mounts = append(mounts, dbcreator.Mount{Source: c.ConfigFile, Target: configPath, ReadOnly: true})
+	}
+	cmd, err := c.serverArgs()
+	if err != nil {
+		return dbcreator.ServiceDefinition{}, err
+	}
+	cmd = append(cmd, c.authArgs(opts)...)
+	var env []string
+	if opts.Password != "" {
+		env = append(env, dbcreator.DockerEnv("REDISCLI_AUTH", opts.Password))
+	}
+	return dbcreator.ServiceDefinition{
+		Spec: dbcreator.ContainerSpec{
+			Name:         opts.ContainerName,
+			Image:        image,
+			Tag:          opts.DockerTag,
+			Labels:       dbcreator.CreateLabels("redis", opts),
+			Port:         port,
+			PortBindings: opts.Ports,
+			Mounts:       mounts,
+			Env:          env,
+			Cmd:          cmd,
+		},
+		HealthCheck: healthCheck(opts),
+	}, nil
+}
 
-	return shell.Run("docker", args...)
+func (c Creator) Create(ctx context.Context, backend dbcreator.Backend, tx *dbcreator.Transaction, opts dbcreator.CreateOptions) (string, error) {
+	def, err := c.GetServiceDefinition(opts)
+	if err != nil {
+		return "", err
+	}
+	contID, err := dbcreator.RunContainer(ctx, backend, tx, def.Spec)
+	if err != nil || !opts.Wait {
+		return contID, err
+	}
+
+	logger := dbcreator.NewProgressLogger(ctx, opts.Verbose, opts.Quiet)
+	defer logger.Done()
+	probe := dbcreator.ExecProbe(backend, contID, def.HealthCheck...)
+	return contID, tx.Step("waiting for the database", func() error {
+		return dbcreator.WaitForReady(ctx, backend, contID, opts.Timeout, &logger, probe, wait.Opts{})
+	})
 }
 
+var (
+	ErrUserWithoutPassword    error = errors.New("redis user requires a password")
+	ErrACLWithoutUser         error = errors.New("ACL rules can only be applied to a user, other than the default one")
+	ErrACLUnsupported         error = errors.New("ACL users require Redis 6.0 or newer, use a newer --tag or a password without --user")
+	ErrACLChannelsUnsupported error = errors.New("ACL channel rules require Redis 6.2 or newer, use a newer --tag or --acl without them")
+)
+
 func (c Creator) ValidatePassword(password string) error {
-	return nil
+	return dbcreator.CheckPassword(password)
+}
+
+func (c Creator) GetConnectionInfo(opts dbcreator.CreateOptions) dbcreator.ConnectionInfo {
+	info := dbcreator.NewConnectionInfo(opts, port)
+	// database names aren't supported, so it's always empty
+	info.Database = ""
+	uri := url.URL{
+		Scheme: "redis",
+		Host:   info.Address(),
+		Path:   "/0",
+	}
+	if info.Password != "" {
+		uri.User = url.UserPassword(info.User, info.Password)
+	}
+	info.ConnectionStrings = []dbcreator.ConnectionString{
+		{Format: "uri", Value: uri.String()},
+	}
+	return info
 }
@@ -0,0 +1,107 @@
+package redis
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/religiosa1/init-docker-db/dbcreator"
+)
+
+// DefaultACLRules are the permissions of the created ACL user, if no rules are
+// provided: all keys, channels and commands
+const DefaultACLRules = "~* &* +@all"
+
+// default rules for Redis 6.0, which has no channel rules, but allows all
+// channels by default
+const legacyACLRules = "~* +@all"
+
+// versionTagPattern matches the version of the image tags, e.g. "6.2",
+// "7.4.1-alpine" or "5"
+var versionTagPattern = regexp.MustCompile(`^(\d+)(?:\.(\d+))?(?:[.-]|$)`)
+
+// name of the built-in user, authenticated with requirepass
+const defaultUser = "default"
+
+// authArgs returns redis-server arguments, enabling the auth. Without a user
+// (or for the default one) the password is set with requirepass, otherwise an
+// ACL user is created and the default user is disabled, so the instance is
+// accessible only with the provided credentials.
+// https://redis.io/docs/latest/operate/oss_and_stack/management/security/acl/
+func (c Creator) authArgs(opts dbcreator.CreateOptions) []string {
+	if opts.Password == "" {
+		return nil
+	}
+	if isDefaultUser(opts.User) {
+		return []string{"--requirepass", opts.Password}
+	}
+	args := []string{"--user", defaultUser, "off", "--user", opts.User, "on", ">" + opts.Password}
+	return append(args, strings.Fields(c.aclRules(opts.DockerTag))...)
+}
+
+func (c Creator) aclRules(tag string) string {
+	if c.ACLRules != "" {
+		return c.ACLRules
+	}
+	if !isVersionAtLeast(tag, 6, 2) {
+		return legacyACLRules
+	}
+	return DefaultACLRules
+}
+
+// checkACL checks, that the image of the tag supports the ACL user and its
+// rules, as otherwise the server fails to start with an unclear error
+func (c Creator) checkACL(opts dbcreator.CreateOptions) error {
+	if isDefaultUser(opts.User) {
+		return nil
+	}
+	if !isVersionAtLeast(opts.DockerTag, 6, 0) {
+		return ErrACLUnsupported
+	}
+	if !isVersionAtLeast(opts.DockerTag, 6, 2) && hasChannelRules(c.ACLRules) {
+		return ErrACLChannelsUnsupported
+	}
+	return nil
+}
+
+func hasChannelRules(rules string) bool {
+	return slices.ContainsFunc(strings.Fields(rules), func(rule string) bool {
+		return strings.HasPrefix(rule, "&") ||
+			strings.EqualFold(rule, "allchannels") ||
+			strings.EqualFold(rule, "resetchannels")
+	})
+}
+
+// isVersionAtLeast reports whether the image of the tag is of the version or
+// newer. Tags without a version, e.g. "latest" or "alpine", refer to the latest
+// release, and tags without the minor version, e.g. "6", to the latest minor
+// one, so they're considered to be new enough.
+func isVersionAtLeast(tag string, major int, minor int) bool {
+	match := versionTagPattern.FindStringSubmatch(tag)
+	if match == nil {
+		return true
+	}
+	tagMajor, _ := strconv.Atoi(match[1])
+	if tagMajor != major || match[2] == "" {
+		return tagMajor >= major
+	}
+	tagMinor, _ := strconv.Atoi(match[2])
+	return tagMinor >= minor
+}
+
+// healthCheck pings the server, authenticating as the created user. The
+// password is read by redis-cli from REDISCLI_AUTH env variable, so it doesn't
+// show up in the command. redis-cli exits with zero code even on error
+// replies, so checking the output.
+func healthCheck(opts dbcreator.CreateOptions) []string {
+	cmd := "redis-cli ping | grep -q PONG"
+	if opts.Password != "" && !isDefaultUser(opts.User) {
+		cmd = "redis-cli --user " + dbcreator.Quote(opts.User) + " ping | grep -q PONG"
+	}
+	return []string{"sh", "-c", cmd}
+}
+
+func isDefaultUser(user string) bool {
+	return user == "" || user == defaultUser
+}
@@ -0,0 +1,108 @@
+package redis
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Persistence modes
+// https://redis.io/docs/latest/operate/oss_and_stack/management/persistence/
+const (
+	// no persistence, e.g. for a pure cache
+	PersistenceNone = "none"
+	// point-in-time snapshots at the save intervals
+	PersistenceRDB = "rdb"
+	// append only file, logging every write operation
+	PersistenceAOF = "aof"
+)
+
+// DefaultSave are the RDB snapshot intervals, used if none are provided: after
+// 60 seconds, if at least 1 key changed
+const DefaultSave = "60 1"
+
+// path of the mounted config file inside of the container
+const configPath = "/usr/local/etc/redis/redis.conf"
+
+var (
+	ErrInvalidSave       error = errors.New(`invalid save intervals, must be pairs of seconds and changes, e.g. "3600 1 300 100"`)
+	ErrInvalidMaxMemory  error = errors.New(`invalid maxmemory value, must be a number of bytes with an optional unit, e.g. "100mb"`)
+	ErrSaveNotRDB        error = errors.New("save intervals can only be used with rdb persistence")
+	ErrAppendFsyncNotAOF error = errors.New("fsync policy can only be used with aof persistence")
+)
+
+var maxMemoryPattern *regexp.Regexp = regexp.MustCompile(`(?i)^\d+([kmg]b?)?$`)
+
+// serverArgs returns redis-server command with the configuration options.
+// Options passed on the command line override the ones of the config file, so
+// without explicitly provided persistence mode the config file's one is used.
+func (c Creator) serverArgs() ([]string, error) {
+	args := []string{"redis-server"}
+	if c.ConfigFile != "" {
+		args = append(args, configPath)
+	}
+
+	persistence := c.Persistence
+	if persistence == "" && (c.ConfigFile == "" || c.Save != "") {
+		persistence = PersistenceRDB
+	}
+	if persistence != PersistenceRDB && c.Save != "" {
+		return nil, ErrSaveNotRDB
+	}
+	if persistence != PersistenceAOF && c.AppendFsync != "" {
+		return nil, ErrAppendFsyncNotAOF
+	}
+	switch persistence {
+	case PersistenceNone:
+		args = append(args, "--save", "", "--appendonly", "no")
+	case PersistenceRDB:
+		save, err := parseSave(c.Save)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, "--save")
+		args = append(args, save...)
+	case PersistenceAOF:
+		args = append(args, "--save", "", "--appendonly", "yes")
+		if c.AppendFsync != "" {
+			args = append(args, "--appendfsync", c.AppendFsync)
+		}
+	case "":
+	default:
+		return nil, fmt.Errorf("unknown persistence mode '%s', must be one of: none, rdb, aof", persistence)
+	}
+
+	if c.MaxMemory != "" {
+		if !maxMemoryPattern.MatchString(c.MaxMemory) {
+			return nil, ErrInvalidMaxMemory
+		}
+		args = append(args, "--maxmemory", c.MaxMemory)
+	}
+	if c.MaxMemoryPolicy != "" {
+		args = append(args, "--maxmemory-policy", c.MaxMemoryPolicy)
+	}
+	if c.ConfigFile == "" {
+		args = append(args, "--loglevel", "warning")
+	}
+	return args, nil
+}
+
+// parseSave splits the save intervals into separate arguments, validating
+// they're pairs of non-negative integers
+func parseSave(save string) ([]string, error) {
+	if save == "" {
+		save = DefaultSave
+	}
+	fields := strings.Fields(save)
+	if len(fields) == 0 || len(fields)%2 != 0 {
+		return nil, ErrInvalidSave
+	}
+	for _, field := range fields {
+		if _, err := strconv.ParseUint(field, 10, 32); err != nil {
+			return nil, ErrInvalidSave
+		}
+	}
+	return fields, nil
+}
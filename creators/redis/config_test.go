@@ -0,0 +1,69 @@
+package redis
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestServerArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		creator Creator
+		want    []string
+		err     error
+	}{
+		{
+			name: "defaults",
+			want: []string{"redis-server", "--save", "60", "1", "--loglevel", "warning"},
+		},
+		{
+			name:    "no persistence",
+			creator: Creator{Persistence: PersistenceNone},
+			want:    []string{"redis-server", "--save", "", "--appendonly", "no", "--loglevel", "warning"},
+		},
+		{
+			name:    "rdb intervals",
+			creator: Creator{Save: " 3600 1  300 100 "},
+			want:    []string{"redis-server", "--save", "3600", "1", "300", "100", "--loglevel", "warning"},
+		},
+		{
+			name:    "aof",
+			creator: Creator{Persistence: PersistenceAOF, AppendFsync: "always"},
+			want:    []string{"redis-server", "--save", "", "--appendonly", "yes", "--appendfsync", "always", "--loglevel", "warning"},
+		},
+		{
+			name:    "eviction",
+			creator: Creator{Persistence: PersistenceNone, MaxMemory: "100MB", MaxMemoryPolicy: "allkeys-lru"},
+			want: []string{
+				"redis-server", "--save", "", "--appendonly", "no",
+				"--maxmemory", "100MB", "--maxmemory-policy", "allkeys-lru", "--loglevel", "warning",
+			},
+		},
+		{
+			name:    "config file",
+			creator: Creator{ConfigFile: "/tmp/redis.conf"},
+			want:    []string{"redis-server", configPath},
+		},
+		{
+			name:    "config file with overrides",
+			creator: Creator{ConfigFile: "/tmp/redis.conf", Save: "10 1", MaxMemory: "1gb"},
+			want:    []string{"redis-server", configPath, "--save", "10", "1", "--maxmemory", "1gb"},
+		},
+		{name: "odd save intervals", creator: Creator{Save: "60 1 300"}, err: ErrInvalidSave},
+		{name: "non-numeric save intervals", creator: Creator{Save: "1m 1"}, err: ErrInvalidSave},
+		{name: "save without rdb", creator: Creator{Persistence: PersistenceAOF, Save: "60 1"}, err: ErrSaveNotRDB},
+		{name: "fsync without aof", creator: Creator{AppendFsync: "always"}, err: ErrAppendFsyncNotAOF},
+		{name: "invalid maxmemory", creator: Creator{MaxMemory: "1tb"}, err: ErrInvalidMaxMemory},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.creator.serverArgs()
+			if err != tt.err {
+				t.Fatalf("Want '%v', got: '%v'", tt.err, err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Want %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,295 @@
+package dbcreator
+
+import (
+	"archive/tar"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/religiosa1/init-docker-db/engineapi"
+)
+
+// APIBackend manages containers through the Engine HTTP API, so the runtime's
+// CLI doesn't have to be installed
+type APIBackend struct {
+	client  *engineapi.Client
+	runtime Runtime
+	verbose bool
+	out     io.Writer
+}
+
+var _ Backend = (*APIBackend)(nil)
+
+// NewAPIBackend creates a backend, talking to the engine through the client.
+// Runtime's quirks are followed, as podman's API is docker-compatible, but
+// inherits its image resolution and rootless specifics. IDs of the started
+// containers are printed to out, as the CLI does.
+func NewAPIBackend(client *engineapi.Client, runtime Runtime, verbose bool, out io.Writer) *APIBackend {
+	if out == nil {
+		out = os.Stdout
+	}
+	return &APIBackend{client: client, runtime: runtime, verbose: verbose, out: out}
+}
+
+func (b *APIBackend) Runtime() Runtime {
+	return b.runtime
+}
+
+func (b *APIBackend) DryRun() bool {
+	return false
+}
+
+func (b *APIBackend) Run(ctx context.Context, spec ContainerSpec) (string, error) {
+	contID, err := b.create(ctx, spec)
+	if err != nil {
+		return "", err
+	}
+	if err := b.client.ContainerStart(ctx, contID); err != nil {
+		return contID, fmt.Errorf("error starting the container: %w", err)
+	}
+	fmt.Fprintln(b.out, contID)
+	return contID, nil
+}
+
+func (b *APIBackend) RunOnce(ctx context.Context, spec ContainerSpec) (string, error) {
+	contID, err := b.create(ctx, spec)
+	if err != nil {
+		return "", err
+	}
+	defer b.client.ContainerRemove(context.Background(), contID, true, true)
+
+	if err := b.client.ContainerStart(ctx, contID); err != nil {
+		return "", fmt.Errorf("error starting the container: %w", err)
+	}
+	state, err := b.waitForExit(ctx, contID)
+	if err != nil {
+		return "", err
+	}
+	out, err := b.client.ContainerLogs(ctx, contID, 0)
+	if err != nil {
+		return "", err
+	}
+	if state.ExitCode != 0 {
+		return out, withOutput(&ExitError{Code: state.ExitCode}, out)
+	}
+	return out, nil
+}
+
+func (b *APIBackend) waitForExit(ctx context.Context, contID string) (engineapi.ContainerState, error) {
+	ticker := time.NewTicker(200 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		state, err := b.client.ContainerInspect(ctx, contID)
+		if err != nil || !state.Running {
+			return state, err
+		}
+		select {
+		case <-ctx.Done():
+			return state, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// create creates the container, pulling its image first if it's missing
+func (b *APIBackend) create(ctx context.Context, spec ContainerSpec) (string, error) {
+	config := b.containerConfig(spec)
+	b.log("creating container %s from %s", spec.Name, config.Image)
+	contID, err := b.client.ContainerCreate(ctx, spec.Name, config)
+	if engineapi.IsNotFound(err) {
+		fmt.Fprintf(os.Stderr, "Pulling image %s...\n", config.Image)
+		if err := b.client.ImagePull(ctx, b.runtime.ImageName(spec.Image), spec.Tag); err != nil {
+			return "", fmt.Errorf("error pulling image %s: %w", config.Image, err)
+		}
+		contID, err = b.client.ContainerCreate(ctx, spec.Name, config)
+	}
+	if err != nil {
+		return "", fmt.Errorf("error creating the container: %w", err)
+	}
+	return contID, nil
+}
+
+func (b *APIBackend) containerConfig(spec ContainerSpec) engineapi.ContainerConfig {
+	config := engineapi.ContainerConfig{
+		Hostname: spec.Hostname,
+		User:     spec.User,
+		Env:      spec.Env,
+		Cmd:      spec.Cmd,
+		Image:    b.runtime.Image(spec.Image, spec.Tag),
+	}
+	if spec.Entrypoint != "" {
+		config.Entrypoint = []string{spec.Entrypoint}
+	}
+	if len(spec.Labels) > 0 {
+		config.Labels = make(map[string]string, len(spec.Labels))
+		for _, label := range spec.Labels {
+			key, value, _ := strings.Cut(label, "=")
+			config.Labels[key] = value
+		}
+	}
+	if len(spec.PortBindings) > 0 {
+		port := fmt.Sprintf("%d/tcp", spec.Port)
+		config.ExposedPorts = map[string]struct{}{port: {}}
+		bindings := make([]engineapi.PortBinding, len(spec.PortBindings))
+		for i, binding := range spec.PortBindings {
+			bindings[i] = toAPIPortBinding(binding)
+		}
+		config.HostConfig.PortBindings = map[string][]engineapi.PortBinding{port: bindings}
+	}
+	for _, mount := range spec.Mounts {
+		config.HostConfig.Binds = append(config.HostConfig.Binds, mount.String())
+	}
+	if b.runtime.KeepIDForBindMounts && spec.HasBindMounts() {
+		config.HostConfig.UsernsMode = "keep-id"
+	}
+	return config
+}
+
+// toAPIPortBinding splits the host binding of `-p` format, e.g.
+// "127.0.0.1:5432", "[::1]:5432" or "5432", into the IP and the port
+func toAPIPortBinding(binding string) engineapi.PortBinding {
+	i := strings.LastIndex(binding, ":")
+	if i < 0 {
+		return engineapi.PortBinding{HostPort: binding}
+	}
+	ip := strings.TrimSuffix(strings.TrimPrefix(binding[:i], "["), "]")
+	return engineapi.PortBinding{HostIP: ip, HostPort: binding[i+1:]}
+}
+
+func (b *APIBackend) Exec(ctx context.Context, contID string, cmd ...string) (string, error) {
+	b.log("exec %s", makeShellCmdString(contID, cmd...))
+	out, code, err := b.client.Exec(ctx, contID, cmd)
+	if err != nil {
+		return out, err
+	}
+	if code != 0 {
+		return out, &ExitError{Code: code}
+	}
+	return out, nil
+}
+
+func (b *APIBackend) CopyTo(ctx context.Context, contID string, src string, dst string) error {
+	file, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	b.log("copying %s to %s:%s", src, contID, dst)
+	// archive is streamed, as init scripts can be sizeable dumps
+	r, w := io.Pipe()
+	go func() {
+		tw := tar.NewWriter(w)
+		err := tw.WriteHeader(&tar.Header{
+			Name:    path.Base(dst),
+			Mode:    int64(info.Mode().Perm()),
+			Size:    info.Size(),
+			ModTime: info.ModTime(),
+		})
+		if err == nil {
+			_, err = io.Copy(tw, file)
+		}
+		if err == nil {
+			err = tw.Close()
+		}
+		w.CloseWithError(err)
+	}()
+	err = b.client.CopyToContainer(ctx, contID, path.Dir(dst), r)
+	r.Close()
+	return err
+}
+
+func (b *APIBackend) IsRunning(ctx context.Context, contID string) (bool, error) {
+	state, err := b.client.ContainerInspect(ctx, contID)
+	return state.Running, err
+}
+
+func (b *APIBackend) Logs(ctx context.Context, contID string, tail int) (string, error) {
+	return b.client.ContainerLogs(ctx, contID, tail)
+}
+
+func (b *APIBackend) Stop(ctx context.Context, contIDs ...string) error {
+	var errs []error
+	for _, id := range contIDs {
+		b.log("stopping container %s", id)
+		if err := b.client.ContainerStop(ctx, id); err != nil {
+			errs = append(errs, fmt.Errorf("error stopping container %s: %w", id, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (b *APIBackend) Remove(ctx context.Context, contIDs ...string) error {
+	var errs []error
+	for _, id := range contIDs {
+		b.log("removing container %s", id)
+		if err := b.client.ContainerRemove(ctx, id, true, true); err != nil {
+			errs = append(errs, fmt.Errorf("error removing container %s: %w", id, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (b *APIBackend) VolumeExists(ctx context.Context, name string) (bool, error) {
+	return b.client.VolumeExists(ctx, name)
+}
+
+func (b *APIBackend) RemoveVolume(ctx context.Context, name string) error {
+	b.log("removing volume %s", name)
+	return b.client.VolumeRemove(ctx, name)
+}
+
+func (b *APIBackend) List(ctx context.Context, label string) ([]ContainerSummary, error) {
+	list, err := b.client.ContainerList(ctx, label)
+	if err != nil {
+		return nil, err
+	}
+	containers := make([]ContainerSummary, len(list))
+	for i, c := range list {
+		names := make([]string, len(c.Names))
+		for j, name := range c.Names {
+			names[j] = strings.TrimPrefix(name, "/")
+		}
+		containers[i] = ContainerSummary{
+			ID:     c.ID,
+			Name:   strings.Join(names, ","),
+			Image:  c.Image,
+			Ports:  formatPorts(c.Ports),
+			Status: c.Status,
+			Labels: c.Labels,
+		}
+	}
+	return containers, nil
+}
+
+// formatPorts formats the ports the same way `docker ps` does
+func formatPorts(ports []engineapi.Port) string {
+	formatted := make([]string, len(ports))
+	for i, p := range ports {
+		if p.PublicPort == 0 {
+			formatted[i] = fmt.Sprintf("%d/%s", p.PrivatePort, p.Type)
+			continue
+		}
+		ip := p.IP
+		if strings.Contains(ip, ":") {
+			ip = "[" + ip + "]"
+		}
+		formatted[i] = fmt.Sprintf("%s:%d->%d/%s", ip, p.PublicPort, p.PrivatePort, p.Type)
+	}
+	return strings.Join(formatted, ", ")
+}
+
+func (b *APIBackend) log(format string, args ...any) {
+	if b.verbose {
+		fmt.Fprintf(os.Stderr, format+"\n", args...)
+	}
+}
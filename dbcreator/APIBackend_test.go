@@ -0,0 +1,38 @@
+package dbcreator
+
+import (
+	"testing"
+
+	"github.com/religiosa1/init-docker-db/engineapi"
+)
+
+func Test_toAPIPortBinding(t *testing.T) {
+	cases := [...]struct {
+		binding string
+		want    engineapi.PortBinding
+	}{
+		{"127.0.0.1:5432", engineapi.PortBinding{HostIP: "127.0.0.1", HostPort: "5432"}},
+		{"[::1]:5432", engineapi.PortBinding{HostIP: "::1", HostPort: "5432"}},
+		{"0.0.0.0:", engineapi.PortBinding{HostIP: "0.0.0.0", HostPort: ""}},
+		{"5432", engineapi.PortBinding{HostPort: "5432"}},
+	}
+	for _, tt := range cases {
+		t.Run(tt.binding, func(t *testing.T) {
+			if got := toAPIPortBinding(tt.binding); got != tt.want {
+				t.Errorf("Unexpected value, want %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_formatPorts(t *testing.T) {
+	got := formatPorts([]engineapi.Port{
+		{IP: "127.0.0.1", PrivatePort: 5432, PublicPort: 5432, Type: "tcp"},
+		{IP: "::1", PrivatePort: 5432, PublicPort: 5432, Type: "tcp"},
+		{PrivatePort: 6379, Type: "tcp"},
+	})
+	want := "127.0.0.1:5432->5432/tcp, [::1]:5432->5432/tcp, 6379/tcp"
+	if got != want {
+		t.Errorf("Unexpected value, want %s, got %s", want, got)
+	}
+}
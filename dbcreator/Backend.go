@@ -0,0 +1,71 @@
+package dbcreator
+
+import (
+	"context"
+	"fmt"
+)
+
+// Backend manages containers, either through the runtime's CLI or directly
+// through the Engine API
+type Backend interface {
+	// Runtime, whose quirks the backend follows
+	Runtime() Runtime
+	// DryRun reports whether the backend only prints the commands, without
+	// creating anything
+	DryRun() bool
+	// Run creates and starts a detached container, returning its ID. ID is
+	// returned if the container was created, even if it failed to start.
+	Run(ctx context.Context, spec ContainerSpec) (string, error)
+	// RunOnce runs a container until it exits, removing it afterwards. Returns
+	// the container's output.
+	RunOnce(ctx context.Context, spec ContainerSpec) (string, error)
+	// Exec runs the command inside of the running container, returning its
+	// combined output. Non-zero exit code results in ExitError.
+	Exec(ctx context.Context, contID string, cmd ...string) (string, error)
+	// CopyTo copies the host file into the container at dst path
+	CopyTo(ctx context.Context, contID string, src string, dst string) error
+	IsRunning(ctx context.Context, contID string) (bool, error)
+	// Logs returns the last lines of the container's output
+	Logs(ctx context.Context, contID string, tail int) (string, error)
+	Stop(ctx context.Context, contIDs ...string) error
+	// Remove force removes the containers alongside with their anonymous volumes
+	Remove(ctx context.Context, contIDs ...string) error
+	VolumeExists(ctx context.Context, name string) (bool, error)
+	RemoveVolume(ctx context.Context, name string) error
+	// List returns all of the containers with the label, including stopped ones
+	List(ctx context.Context, label string) ([]ContainerSummary, error)
+}
+
+// ContainerSummary is a brief description of an existing container
+type ContainerSummary struct {
+	ID    string
+	Name  string
+	Image string
+	// published ports in `docker ps` format, e.g. "127.0.0.1:5432->5432/tcp"
+	Ports  string
+	Status string
+	// labels stamped on the container by the tool
+	Labels map[string]string
+}
+
+// ExitError is an error of a command, exited with non-zero code
+type ExitError struct {
+	Code int
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.Code)
+}
+
+// RunContainer runs the container with the backend, returning its ID and
+// registering it in the transaction
+func RunContainer(ctx context.Context, backend Backend, tx *Transaction, spec ContainerSpec) (string, error) {
+	var contID string
+	err := tx.Step("starting the container", func() error {
+		var err error
+		contID, err = backend.Run(ctx, spec)
+		tx.AddContainer(contID)
+		return err
+	})
+	return contID, err
+}
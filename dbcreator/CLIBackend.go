@@ -0,0 +1,215 @@
+package dbcreator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// CLIBackend manages containers by running the runtime's CLI, e.g. `docker`.
+// It's the only backend supporting dry run, as it just writes the commands as
+// a shell script.
+type CLIBackend struct {
+	shell Shell
+}
+
+var _ Backend = (*CLIBackend)(nil)
+
+// NewCLIBackend creates a backend, running the CLI of the shell's runtime
+func NewCLIBackend(shell Shell) *CLIBackend {
+	return &CLIBackend{shell: shell}
+}
+
+func (b *CLIBackend) Runtime() Runtime {
+	return b.shell.Runtime()
+}
+
+func (b *CLIBackend) DryRun() bool {
+	return b.shell.dryRun
+}
+
+func (b *CLIBackend) Run(ctx context.Context, spec ContainerSpec) (string, error) {
+	args := b.runArgs(spec, true)
+	if b.DryRun() {
+		return b.shell.script.CaptureContainerID(b.shell.out, b.cli(), args...), nil
+	}
+	// container can be created, but fail to start, e.g. if the port is
+	// already allocated, in which case docker doesn't print its ID, so
+	// we're asking docker to write it into a file as soon as it's created
+	dir, err := os.MkdirTemp("", "init-docker-db-")
+	if err != nil {
+		return "", fmt.Errorf("error creating temporary directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+	cidFile := filepath.Join(dir, "cid")
+	args = append([]string{args[0], "--cidfile", cidFile}, args[1:]...)
+
+	out, err := b.shell.RunWithTeeOutput(ctx, b.cli(), args...)
+	contID := strings.TrimSpace(out)
+	if cid, readErr := os.ReadFile(cidFile); readErr == nil {
+		contID = strings.TrimSpace(string(cid))
+	}
+	return contID, err
+}
+
+func (b *CLIBackend) RunOnce(ctx context.Context, spec ContainerSpec) (string, error) {
+	out, err := b.shell.RunWithOutput(ctx, b.cli(), b.runArgs(spec, false)...)
+	return out, withOutput(err, out)
+}
+
+// runArgs creates `run` command arguments for the spec, either for a detached
+// container or for a one-off container, removed after it exits
+func (b *CLIBackend) runArgs(spec ContainerSpec, detached bool) []string {
+	runtime := b.Runtime()
+	args := []string{"run"}
+	if spec.Name != "" {
+		args = append(args, "--name", spec.Name)
+	}
+	if spec.Hostname != "" {
+		args = append(args, "--hostname", spec.Hostname)
+	}
+	if !detached {
+		args = append(args, "--rm")
+	}
+	if spec.User != "" {
+		args = append(args, "--user", spec.User)
+	}
+	for _, env := range spec.Env {
+		args = append(args, "-e", env)
+	}
+	for _, label := range spec.Labels {
+		args = append(args, "--label", label)
+	}
+	for _, binding := range spec.PortBindings {
+		args = append(args, "-p", fmt.Sprintf("%s:%d", binding, spec.Port))
+	}
+	for _, mount := range spec.Mounts {
+		args = append(args, "-v", mount.String())
+	}
+	if runtime.KeepIDForBindMounts && spec.HasBindMounts() {
+		args = append(args, "--userns=keep-id")
+	}
+	if spec.Entrypoint != "" {
+		args = append(args, "--entrypoint", spec.Entrypoint)
+	}
+	if detached {
+		args = append(args, "-d")
+	}
+	args = append(args, runtime.Image(spec.Image, spec.Tag))
+	return append(args, spec.Cmd...)
+}
+
+func (b *CLIBackend) Exec(ctx context.Context, contID string, cmd ...string) (string, error) {
+	out, err := b.shell.RunWithOutput(ctx, b.cli(), append([]string{"exec", contID}, cmd...)...)
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() >= 0 {
+		return out, &ExitError{Code: exitErr.ExitCode()}
+	}
+	return out, err
+}
+
+func (b *CLIBackend) CopyTo(ctx context.Context, contID string, src string, dst string) error {
+	out, err := b.shell.RunWithOutput(ctx, b.cli(), "cp", src, fmt.Sprintf("%s:%s", contID, dst))
+	return withOutput(err, out)
+}
+
+func (b *CLIBackend) IsRunning(ctx context.Context, contID string) (bool, error) {
+	out, err := b.shell.RunWithOutput(ctx, b.cli(), "inspect", "--format", "{{.State.Running}}", contID)
+	if err != nil {
+		return false, withOutput(err, out)
+	}
+	return strings.TrimSpace(out) == "true", nil
+}
+
+func (b *CLIBackend) Logs(ctx context.Context, contID string, tail int) (string, error) {
+	out, err := b.shell.RunWithOutput(ctx, b.cli(), "logs", "--tail", fmt.Sprint(tail), contID)
+	return out, withOutput(err, out)
+}
+
+func (b *CLIBackend) Stop(ctx context.Context, contIDs ...string) error {
+	out, err := b.shell.RunWithOutput(ctx, b.cli(), append([]string{"stop"}, contIDs...)...)
+	return withOutput(err, out)
+}
+
+func (b *CLIBackend) Remove(ctx context.Context, contIDs ...string) error {
+	out, err := b.shell.RunWithOutput(ctx, b.cli(), append([]string{"rm", "--force", "--volumes"}, contIDs...)...)
+	return withOutput(err, out)
+}
+
+func (b *CLIBackend) VolumeExists(ctx context.Context, name string) (bool, error) {
+	// CLI doesn't allow to distinguish a missing volume from other errors
+	_, err := b.shell.RunWithOutput(ctx, b.cli(), "volume", "inspect", name)
+	return err == nil, nil
+}
+
+func (b *CLIBackend) RemoveVolume(ctx context.Context, name string) error {
+	out, err := b.shell.RunWithOutput(ctx, b.cli(), "volume", "rm", name)
+	return withOutput(err, out)
+}
+
+var listLabels = [...]string{LabelEngine, LabelDatabase, LabelUser, LabelVersion, LabelCreatedAt}
+
+func (b *CLIBackend) List(ctx context.Context, label string) ([]ContainerSummary, error) {
+	fields := []string{"{{.ID}}", "{{.Names}}", "{{.Image}}", "{{.Ports}}", "{{.Status}}"}
+	for _, l := range listLabels {
+		fields = append(fields, fmt.Sprintf("{{.Label %q}}", l))
+	}
+	out, err := b.shell.RunWithOutput(
+		ctx, b.cli(), "ps", "--all",
+		"--filter", "label="+label,
+		"--format", strings.Join(fields, "\t"),
+	)
+	if err != nil {
+		return nil, withOutput(err, out)
+	}
+	return parsePsOutput(out)
+}
+
+func parsePsOutput(out string) ([]ContainerSummary, error) {
+	var containers []ContainerSummary
+	for line := range strings.Lines(out) {
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		if len(fields) != 5+len(listLabels) {
+			return nil, fmt.Errorf("unexpected ps output line: %q", line)
+		}
+		labels := make(map[string]string, len(listLabels))
+		for i, l := range listLabels {
+			if value := fields[5+i]; value != "" {
+				labels[l] = value
+			}
+		}
+		containers = append(containers, ContainerSummary{
+			ID:     fields[0],
+			Name:   fields[1],
+			Image:  fields[2],
+			Ports:  fields[3],
+			Status: fields[4],
+			Labels: labels,
+		})
+	}
+	return containers, nil
+}
+
+func (b *CLIBackend) cli() string {
+	return b.shell.Runtime().Name
+}
+
+// withOutput appends the command's output to the error, as it usually
+// contains the actual reason of the failure
+func withOutput(err error, out string) error {
+	if err == nil {
+		return nil
+	}
+	if out = strings.TrimSpace(out); out != "" {
+		return fmt.Errorf("%w\n%s", err, out)
+	}
+	return err
+}
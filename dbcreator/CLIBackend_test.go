@@ -0,0 +1,75 @@
+package dbcreator
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestCLIBackendRunArgs(t *testing.T) {
+	spec := ContainerSpec{
+		Name:         "foo",
+		Image:        "postgres",
+		Tag:          "16",
+		Env:          []string{"POSTGRES_USER=foo"},
+		Port:         5432,
+		PortBindings: []string{"127.0.0.1:5432"},
+		Mounts:       []Mount{{Source: "/srv/pgdata", Target: "/var/lib/postgresql/data"}},
+		Cmd:          []string{"-c", "fsync=off"},
+	}
+
+	t.Run("creates detached docker run arguments", func(t *testing.T) {
+		got := NewCLIBackend(NewShell(true, false)).runArgs(spec, true)
+		want := []string{
+			"run", "--name", "foo", "-e", "POSTGRES_USER=foo",
+			"-p", "127.0.0.1:5432:5432", "-v", "/srv/pgdata:/var/lib/postgresql/data",
+			"-d", "postgres:16", "-c", "fsync=off",
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("Unexpected value, want %v, got %v", want, got)
+		}
+	})
+
+	t.Run("follows podman quirks", func(t *testing.T) {
+		got := NewCLIBackend(NewShell(true, false).WithRuntime(Podman)).runArgs(spec, false)
+		if !slices.Contains(got, "--userns=keep-id") || !slices.Contains(got, "--rm") {
+			t.Errorf("Expected keep-id user namespace for a one-off container, got %v", got)
+		}
+		if !slices.Contains(got, "docker.io/library/postgres:16") {
+			t.Errorf("Expected a qualified image, got %v", got)
+		}
+	})
+}
+
+func Test_parsePsOutput(t *testing.T) {
+	t.Run("parses tab separated ps output", func(t *testing.T) {
+		out := "abc123\tfoo-bar\tpostgres:latest\t127.0.0.1:5432->5432/tcp\tUp 2 hours\tpostgres\tdb\tuser\t1.0.0\t2025-11-20T10:00:00Z\n" +
+			"def456\tbaz-qux\tredis:7\t\tExited (0) 1 day ago\tredis\t\t\t\t\n"
+		got, err := parsePsOutput(out)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 2 {
+			t.Fatalf("Unexpected number of containers, want 2, got %d", len(got))
+		}
+		if got[0].Name != "foo-bar" || got[0].Labels[LabelEngine] != "postgres" || got[0].Status != "Up 2 hours" {
+			t.Errorf("Unexpected container value: %+v", got[0])
+		}
+		if _, ok := got[1].Labels[LabelCreatedAt]; ok {
+			t.Errorf("Expected empty labels to be omitted, got %v", got[1].Labels)
+		}
+	})
+
+	t.Run("empty output results in empty list", func(t *testing.T) {
+		got, err := parsePsOutput("")
+		if err != nil || len(got) != 0 {
+			t.Errorf("Unexpected result: %v, %v", got, err)
+		}
+	})
+
+	t.Run("errors on malformed lines", func(t *testing.T) {
+		_, err := parsePsOutput("abc123\tfoo-bar\n")
+		if err == nil {
+			t.Error("expected parsePsOutput to throw, but it didn't")
+		}
+	})
+}
@@ -0,0 +1,84 @@
+package dbcreator
+
+import (
+	"net"
+	"strconv"
+	"strings"
+)
+
+// ConnectionInfo is the information required to connect to the created database
+type ConnectionInfo struct {
+	Host     string
+	Port     uint16
+	User     string
+	Password string
+	Database string
+	// Ready-to-use connection strings in engine-specific formats
+	ConnectionStrings []ConnectionString
+}
+
+// ConnectionString is a connection string in the specified format, e.g. "uri"
+type ConnectionString struct {
+	Format string
+	Value  string
+}
+
+// NewConnectionInfo creates connection info from the creation options, using
+// the first of the published ports as the database address
+func NewConnectionInfo(opts CreateOptions, containerPort uint16) ConnectionInfo {
+	info := ConnectionInfo{
+		Host:     "localhost",
+		Port:     containerPort,
+		User:     opts.User,
+		Password: opts.Password,
+		Database: opts.Database,
+	}
+	if len(opts.Ports) > 0 {
+		info.Host, info.Port = parsePortBinding(opts.Ports[0], containerPort)
+	}
+	return info
+}
+
+// Address returns host:port pair of the connection, with IPv6 hosts enclosed
+// in square brackets
+func (info ConnectionInfo) Address() string {
+	return net.JoinHostPort(info.Host, strconv.Itoa(int(info.Port)))
+}
+
+// ConnectionString returns a connection string in the specified format
+func (info ConnectionInfo) ConnectionString(format string) (string, bool) {
+	for _, cs := range info.ConnectionStrings {
+		if cs.Format == format {
+			return cs.Value, true
+		}
+	}
+	return "", false
+}
+
+// Formats returns the list of available connection string formats
+func (info ConnectionInfo) Formats() []string {
+	formats := make([]string, len(info.ConnectionStrings))
+	for i, cs := range info.ConnectionStrings {
+		formats[i] = cs.Format
+	}
+	return formats
+}
+
+// parsePortBinding extracts host and port from the docker publish binding
+// in the form of [ip:]port, e.g. "127.0.0.1:5432", "[::1]:5432" or "5432"
+func parsePortBinding(binding string, containerPort uint16) (string, uint16) {
+	host, portStr := "", binding
+	if idx := strings.LastIndex(binding, ":"); idx != -1 {
+		host, portStr = binding[:idx], binding[idx+1:]
+	}
+	host = strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")
+	if ip := net.ParseIP(host); host == "" || (ip != nil && (ip.IsLoopback() || ip.IsUnspecified())) {
+		host = "localhost"
+	}
+	port, err := strconv.ParseUint(portStr, 10, 16)
+	if err != nil || port == 0 {
+		// no host port (random one is assigned by docker) or a port range
+		return host, containerPort
+	}
+	return host, uint16(port)
+}
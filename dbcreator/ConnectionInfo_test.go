@@ -0,0 +1,39 @@
+package dbcreator
+
+import "testing"
+
+func Test_parsePortBinding(t *testing.T) {
+	cases := [...]struct {
+		binding string
+		host    string
+		port    uint16
+	}{
+		{"127.0.0.1:5432", "localhost", 5432},
+		{"[::1]:5433", "localhost", 5433},
+		{"8080", "localhost", 8080},
+		{"0.0.0.0:8080", "localhost", 8080},
+		{"192.168.1.10:8080", "192.168.1.10", 8080},
+		{"[fd00::1]:8080", "fd00::1", 8080},
+		{"127.0.0.1:", "localhost", 1433},
+		{"127.0.0.1:8000-8010", "localhost", 1433},
+	}
+	for _, tt := range cases {
+		t.Run(tt.binding, func(t *testing.T) {
+			host, port := parsePortBinding(tt.binding, 1433)
+			if host != tt.host || port != tt.port {
+				t.Errorf("Unexpected value, want %s %d, got %s %d", tt.host, tt.port, host, port)
+			}
+		})
+	}
+}
+
+func TestConnectionInfoAddress(t *testing.T) {
+	info := NewConnectionInfo(CreateOptions{Ports: []string{"[fd00::1]:8080"}}, 5432)
+	if want, got := "[fd00::1]:8080", info.Address(); want != got {
+		t.Errorf("Unexpected value, want %s, got %s", want, got)
+	}
+	info = NewConnectionInfo(CreateOptions{}, 5432)
+	if want, got := "localhost:5432", info.Address(); want != got {
+		t.Errorf("Unexpected value, want %s, got %s", want, got)
+	}
+}
@@ -0,0 +1,64 @@
+package dbcreator
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// ContainerSpec is a runtime-agnostic definition of a container to be run by
+// a Backend
+type ContainerSpec struct {
+	Name     string
+	Hostname string
+	// image name without the tag, qualified by the backend if its runtime
+	// requires it
+	Image string
+	Tag   string
+	// user to run the container as, image's default if empty
+	User string
+	// environment variables in KEY=VALUE format
+	Env []string
+	// labels in KEY=VALUE format
+	Labels []string
+	// container port, published on each of the host bindings
+	Port uint16
+	// host port with optional IP address, e.g. "127.0.0.1:5432"
+	PortBindings []string
+	Mounts       []Mount
+	// overrides the image's entrypoint, if not empty
+	Entrypoint string
+	// arguments passed to the entrypoint
+	Cmd []string
+}
+
+// Mount is a named volume or a host path, mounted inside of the container
+type Mount struct {
+	// volume name or an absolute host path
+	Source   string
+	Target   string
+	ReadOnly bool
+}
+
+// IsBind reports whether the mount is a host path bind mount
+func (m Mount) IsBind() bool {
+	return filepath.IsAbs(m.Source) || strings.HasPrefix(m.Source, "/")
+}
+
+// String formats the mount in `-v` argument format, e.g. "pgdata:/data:ro"
+func (m Mount) String() string {
+	s := m.Source + ":" + m.Target
+	if m.ReadOnly {
+		s += ":ro"
+	}
+	return s
+}
+
+// HasBindMounts reports whether any of the spec's mounts is a bind mount
+func (s ContainerSpec) HasBindMounts() bool {
+	for _, m := range s.Mounts {
+		if m.IsBind() {
+			return true
+		}
+	}
+	return false
+}
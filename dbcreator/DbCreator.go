@@ -2,47 +2,80 @@
 // e.g. Postgres, MYSQL, etc.
 package dbcreator
 
-import "fmt"
+import (
+	"context"
+	"time"
+)
 
 type CreateOptions struct {
 	ContainerName string
 	Database      string
 	User          string
 	Password      string
+	// password is randomly generated, so it has to be shown to the user
+	GeneratedPassword bool
+	// password of the root account, if it's separate from the user
+	RootPassword string
 	// host port with optional IP address;
 	// see https://docs.docker.com/reference/cli/docker/container/run/#publish
 	Ports     []string
 	DockerTag string
-	Verbose   bool
-	DryRun    bool
+	// named volume or host directory to mount at the database data path
+	Volume string
+	// seed script files or directories to be executed after the database creation
+	InitScripts []string
+	Verbose     bool
+	DryRun      bool
+	// suppress progress indicators, writing any auxiliary output to stderr
+	Quiet bool
+	// wait for the database to be ready to accept connections
+	Wait bool
+	// maximum time to wait for the database readiness
+	Timeout time.Duration
+	// version of the tool, stamped on the container's labels
+	ToolVersion string
 }
 
 // Capabilities are the list of DBCreator capabilities
 type Capabilities struct {
 	DatabaseName bool
+	// user and password are required for the auth
 	UserPassword bool
+	// auth is optional, with a password only or with a user and a password
+	PasswordOnly bool
+	// root account is created alongside with the user, and can have its own
+	// password
+	RootPassword bool
+	InitScripts  bool
+}
+
+// Auth reports whether the database supports password auth
+func (c Capabilities) Auth() bool {
+	return c.UserPassword || c.PasswordOnly
 }
 
 // DefaultOpts are default options for the DBCreator
 type DefaultOpts struct {
+	// docker image name, without the tag
+	Image     string
 	User      string
 	DockerTag string
 	Port      uint16
 	Password  string
+	// database data directory inside of the container
+	DataPath string
 }
 
 type DBCreator interface {
 	GetDefaultOpts() DefaultOpts
 	GetCapabilities() Capabilities
-	Create(shell Shell, opts CreateOptions) error
+	// Create the container, returning its ID. Created resources are registered
+	// in the transaction, so they can be removed if creation fails or ctx is
+	// cancelled.
+	Create(ctx context.Context, backend Backend, tx *Transaction, opts CreateOptions) (string, error)
+	// GetServiceDefinition describes the container declaratively, so it can be
+	// exported instead of being run
+	GetServiceDefinition(opts CreateOptions) (ServiceDefinition, error)
 	ValidatePassword(password string) error
-}
-
-func CreatePortBindingsArgument(containerPort uint16, bindings []string) []string {
-	args := make([]string, len(bindings)*2)
-	for i := range bindings {
-		args[i*2] = "-p"
-		args[i*2+1] = fmt.Sprintf("%s:%d", bindings[i], containerPort)
-	}
-	return args
+	GetConnectionInfo(opts CreateOptions) ConnectionInfo
 }
@@ -0,0 +1,89 @@
+package dbcreator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// InitScriptsDir is the directory, scripts from which are executed by the
+// official images' entrypoints on the first container start
+const InitScriptsDir = "/docker-entrypoint-initdb.d"
+
+// InitScript is a seed script to be executed after the database creation
+type InitScript struct {
+	// absolute path to the script on the host
+	Path string
+	// unique name of the script, prefixed with its index, so scripts are run in
+	// the order they were provided
+	Name string
+}
+
+// Ext returns the script's extension, including compound ones, e.g. ".sql.gz"
+func (s InitScript) Ext() string {
+	return scriptExt(s.Path)
+}
+
+// ResolveInitScripts resolves provided files or directories into a list of
+// init scripts. Directories are expanded (non-recursively) into the files
+// with supported extensions in alphabetical order, while explicitly provided
+// files with unsupported extensions result in an error.
+func ResolveInitScripts(paths []string, extensions []string) ([]InitScript, error) {
+	var files []string
+	for _, path := range paths {
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return nil, fmt.Errorf("error resolving init script path '%s': %w", path, err)
+		}
+		stat, err := os.Stat(abs)
+		if err != nil {
+			return nil, fmt.Errorf("error reading init script '%s': %w", path, err)
+		}
+		if !stat.IsDir() {
+			if !slices.Contains(extensions, scriptExt(abs)) {
+				return nil, fmt.Errorf("unsupported init script '%s', must be one of: %s", path, strings.Join(extensions, ", "))
+			}
+			files = append(files, abs)
+			continue
+		}
+		entries, err := os.ReadDir(abs)
+		if err != nil {
+			return nil, fmt.Errorf("error reading init scripts directory '%s': %w", path, err)
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() && slices.Contains(extensions, scriptExt(entry.Name())) {
+				files = append(files, filepath.Join(abs, entry.Name()))
+			}
+		}
+	}
+
+	scripts := make([]InitScript, len(files))
+	for i, file := range files {
+		scripts[i] = InitScript{
+			Path: file,
+			Name: fmt.Sprintf("%03d-%s", i+1, filepath.Base(file)),
+		}
+	}
+	return scripts, nil
+}
+
+// CreateInitScriptsMounts creates mounts of each of the scripts as a
+// read-only file into the entrypoint's init directory
+func CreateInitScriptsMounts(scripts []InitScript) []Mount {
+	mounts := make([]Mount, len(scripts))
+	for i, script := range scripts {
+		mounts[i] = Mount{Source: script.Path, Target: InitScriptsDir + "/" + script.Name, ReadOnly: true}
+	}
+	return mounts
+}
+
+func scriptExt(path string) string {
+	ext := filepath.Ext(path)
+	switch ext {
+	case ".gz", ".bz2", ".xz", ".zst":
+		return filepath.Ext(strings.TrimSuffix(path, ext)) + ext
+	}
+	return ext
+}
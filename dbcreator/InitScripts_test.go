@@ -0,0 +1,83 @@
+package dbcreator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolveInitScripts(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"b.sql", "a.sql.gz", "README.md", "c.sh"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(dir, "nested.sql"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	extensions := []string{".sql", ".sql.gz"}
+
+	t.Run("expands directories skipping unsupported files", func(t *testing.T) {
+		scripts, err := ResolveInitScripts([]string{dir}, extensions)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []InitScript{
+			{Path: filepath.Join(dir, "a.sql.gz"), Name: "001-a.sql.gz"},
+			{Path: filepath.Join(dir, "b.sql"), Name: "002-b.sql"},
+		}
+		if len(scripts) != len(want) {
+			t.Fatalf("Unexpected scripts: %+v", scripts)
+		}
+		for i := range want {
+			if scripts[i] != want[i] {
+				t.Errorf("Unexpected script, want %+v, got %+v", want[i], scripts[i])
+			}
+		}
+	})
+
+	t.Run("preserves the provided order", func(t *testing.T) {
+		scripts, err := ResolveInitScripts([]string{filepath.Join(dir, "b.sql"), filepath.Join(dir, "a.sql.gz")}, extensions)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(scripts) != 2 || scripts[0].Name != "001-b.sql" || scripts[1].Name != "002-a.sql.gz" {
+			t.Errorf("Unexpected scripts: %+v", scripts)
+		}
+	})
+
+	t.Run("errors on explicitly provided unsupported file", func(t *testing.T) {
+		_, err := ResolveInitScripts([]string{filepath.Join(dir, "c.sh")}, extensions)
+		if err == nil {
+			t.Error("expected ResolveInitScripts to throw, but it didn't")
+		}
+	})
+
+	t.Run("errors on missing file", func(t *testing.T) {
+		_, err := ResolveInitScripts([]string{filepath.Join(dir, "missing.sql")}, extensions)
+		if err == nil {
+			t.Error("expected ResolveInitScripts to throw, but it didn't")
+		}
+	})
+}
+
+func TestInitScriptExt(t *testing.T) {
+	cases := [...]struct {
+		path string
+		ext  string
+	}{
+		{"/foo/bar.sql", ".sql"},
+		{"/foo/bar.sql.gz", ".sql.gz"},
+		{"/foo/bar.js", ".js"},
+		{"/foo/bar.tar.xz", ".tar.xz"},
+		{"/foo/bar", ""},
+	}
+	for _, tt := range cases {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := (InitScript{Path: tt.path}).Ext(); got != tt.ext {
+				t.Errorf("Unexpected value, want %s, got %s", tt.ext, got)
+			}
+		})
+	}
+}
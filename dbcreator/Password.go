@@ -0,0 +1,117 @@
+package dbcreator
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"unicode"
+)
+
+var (
+	ErrPasswordEmpty        error = errors.New("password can't be empty")
+	ErrPasswordControlChars error = errors.New("password can't contain control characters, e.g. new lines or tabs")
+	// root password is provided, while the user is root itself
+	ErrRootPasswordWithRootUser error = errors.New("separate root password can't be used, if the user is root")
+)
+
+// CheckPassword checks the requirements common to all of the databases: the
+// password must be non-empty, as the images refuse to initialize without it,
+// and must not contain control characters, which can't be passed through env
+// and dotenv files
+func CheckPassword(password string) error {
+	if password == "" {
+		return ErrPasswordEmpty
+	}
+	if strings.ContainsFunc(password, unicode.IsControl) {
+		return ErrPasswordControlChars
+	}
+	return nil
+}
+
+// GeneratedPasswordLength is the length of the generated passwords
+const GeneratedPasswordLength = 24
+
+// Character classes of the generated passwords. Special characters are URI
+// unreserved ones, so the password doesn't require escaping in connection
+// strings, shell commands, SQL literals or dotenv files.
+var passwordClasses = [...]string{
+	"abcdefghijklmnopqrstuvwxyz",
+	"ABCDEFGHIJKLMNOPQRSTUVWXYZ",
+	"0123456789",
+	"-_.",
+}
+
+// maximum number of attempts to generate a password, passing the validation
+const maxPasswordAttempts = 10
+
+// GeneratePassword generates a cryptographically random password, containing
+// characters of each class (lower and upper case latin letters, digits and
+// special characters) and starting with a letter, so it's not mistaken for a
+// flag. The password is regenerated until it passes validate.
+func GeneratePassword(validate func(string) error) (string, error) {
+	var err error
+	for range maxPasswordAttempts {
+		var password string
+		password, err = generatePassword(GeneratedPasswordLength)
+		if err != nil {
+			return "", err
+		}
+		if err = validate(password); err == nil {
+			return password, nil
+		}
+	}
+	return "", fmt.Errorf("error generating a password, passing the validation: %w", err)
+}
+
+func generatePassword(length int) (string, error) {
+	var alphabet string
+	for _, class := range passwordClasses {
+		alphabet += class
+	}
+	password := make([]byte, length)
+	// first character is a letter, and each class is guaranteed to be present
+	first, err := randomChar(passwordClasses[0] + passwordClasses[1])
+	if err != nil {
+		return "", err
+	}
+	password[0] = first
+	for i, class := range passwordClasses {
+		if password[i+1], err = randomChar(class); err != nil {
+			return "", err
+		}
+	}
+	for i := len(passwordClasses) + 1; i < length; i++ {
+		if password[i], err = randomChar(alphabet); err != nil {
+			return "", err
+		}
+	}
+	// shuffling everything but the first character, so the guaranteed classes
+	// aren't at the fixed positions
+	for i := length - 1; i > 1; i-- {
+		j, err := randomInt(i)
+		if err != nil {
+			return "", err
+		}
+		password[i], password[j+1] = password[j+1], password[i]
+	}
+	return string(password), nil
+}
+
+func randomChar(chars string) (byte, error) {
+	i, err := randomInt(len(chars))
+	if err != nil {
+		return 0, err
+	}
+	return chars[i], nil
+}
+
+// randomInt returns a uniformly distributed random integer in [0, n)
+func randomInt(n int) (int, error) {
+	i, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		return 0, fmt.Errorf("error generating a random number: %w", err)
+	}
+	return int(i.Int64()), nil
+}
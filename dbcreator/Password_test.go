@@ -0,0 +1,49 @@
+package dbcreator
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestGeneratePassword(t *testing.T) {
+	t.Run("contains each character class", func(t *testing.T) {
+		for range 100 {
+			password, err := GeneratePassword(func(string) error { return nil })
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(password) != GeneratedPasswordLength {
+				t.Fatalf("Unexpected length, want %d, got %d", GeneratedPasswordLength, len(password))
+			}
+			if !strings.ContainsAny(password[:1], passwordClasses[0]+passwordClasses[1]) {
+				t.Errorf("Expected the password to start with a letter, got %s", password)
+			}
+			for _, class := range passwordClasses {
+				if !strings.ContainsAny(password, class) {
+					t.Errorf("Expected the password to contain one of %q, got %s", class, password)
+				}
+			}
+		}
+	})
+
+	t.Run("regenerates rejected passwords", func(t *testing.T) {
+		attempts := 0
+		_, err := GeneratePassword(func(string) error {
+			attempts++
+			if attempts < 3 {
+				return errors.New("rejected")
+			}
+			return nil
+		})
+		if err != nil || attempts != 3 {
+			t.Errorf("Expected the password to be accepted on the third attempt, got %d attempts, error %v", attempts, err)
+		}
+	})
+
+	t.Run("fails if the validation never passes", func(t *testing.T) {
+		if _, err := GeneratePassword(func(string) error { return errors.New("rejected") }); err == nil {
+			t.Error("expected GeneratePassword to throw, but it didn't")
+		}
+	})
+}
@@ -0,0 +1,94 @@
+package dbcreator
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/charmbracelet/huh/spinner"
+	"golang.org/x/term"
+)
+
+// ProgressLogger shows progress of the long running operations with a spinner
+// or, in verbose mode, with plain log messages
+type ProgressLogger struct {
+	ctx        context.Context
+	verbose    bool
+	cancelFunc context.CancelFunc
+	isTerminal bool
+	out        io.Writer
+	wg         sync.WaitGroup
+}
+
+// NewProgressLogger creates a new logger. In quiet mode spinner is never
+// shown and all of the messages are written to stderr. Spinner is stopped,
+// when ctx is cancelled.
+func NewProgressLogger(ctx context.Context, verbose bool, quiet bool) ProgressLogger {
+	if quiet {
+		return ProgressLogger{
+			ctx:     ctx,
+			verbose: verbose,
+			out:     os.Stderr,
+		}
+	}
+	return ProgressLogger{
+		ctx:        ctx,
+		verbose:    verbose,
+		isTerminal: term.IsTerminal(int(os.Stdout.Fd())),
+		out:        os.Stdout,
+	}
+}
+
+// LogState shows the current state of the operation
+func (l *ProgressLogger) LogState(state string) {
+	if l.verbose {
+		_, _ = fmt.Fprintln(l.out, state)
+		return
+	}
+
+	if !l.isTerminal || l.ctx.Err() != nil {
+		return
+	}
+
+	// Cancel previous spinner if running
+	if l.cancelFunc != nil {
+		l.cancelFunc()
+		l.wg.Wait() // Wait for previous spinner to finish
+	}
+
+	// Start new spinner in goroutine
+	ctx, cancel := context.WithCancel(l.ctx)
+	l.cancelFunc = cancel
+
+	l.wg.Add(1)
+	go func() {
+		defer l.wg.Done()
+		spinner.New().
+			Title(state).
+			Context(ctx).
+			Run()
+	}()
+}
+
+// LogVerbose prints a message only in the verbose mode
+func (l *ProgressLogger) LogVerbose(s ...any) {
+	if l.verbose {
+		_, _ = fmt.Fprintln(l.out, s...)
+	}
+}
+
+// Print a message regardless of the verbosity level
+func (l *ProgressLogger) Print(s ...any) {
+	_, _ = fmt.Fprintln(l.out, s...)
+}
+
+// Done stops the spinner, if it's running
+func (l *ProgressLogger) Done() {
+	if l.cancelFunc != nil {
+		l.cancelFunc()
+		l.wg.Wait() // Wait for spinner goroutine to finish
+		l.cancelFunc = nil
+	}
+}
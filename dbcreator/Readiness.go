@@ -0,0 +1,98 @@
+package dbcreator
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/religiosa1/init-docker-db/wait"
+)
+
+// DefaultTimeout is the default time to wait for the database to be ready
+const DefaultTimeout = 60 * time.Second
+
+// defaultProbeTimeout limits a single probe run, so a hung `docker exec`
+// doesn't consume the whole timeout
+const defaultProbeTimeout = 15 * time.Second
+
+// WaitForReady polls the readiness probe with an exponential backoff, until it
+// succeeds, the timeout is reached, ctx is cancelled or the container stops
+// running
+func WaitForReady(
+	ctx context.Context,
+	backend Backend,
+	contID string,
+	timeout time.Duration,
+	logger *ProgressLogger,
+	probe func(ctx context.Context) error,
+	waitOpts wait.Opts,
+) error {
+	logger.LogState("Waiting for db to be up and running...")
+
+	if timeout == 0 {
+		timeout = DefaultTimeout
+	}
+	// dry run can't poll, so the script waits for the probe commands instead
+	if b, ok := backend.(*CLIBackend); ok && b.DryRun() {
+		b.shell.script.Wait(b.shell.out, timeout, func() { _ = probe(ctx) })
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if waitOpts.AttemptTimeout == 0 {
+		waitOpts.AttemptTimeout = defaultProbeTimeout
+	}
+	waitOpts.OnRetry = func(attempt int, err error, delay time.Duration) {
+		logger.LogVerbose(fmt.Sprintf("health check #%d failed: %s, retrying in %s", attempt, err, delay.Round(time.Millisecond)))
+	}
+
+	// there's no point in waiting for the whole timeout, if the container is
+	// dead, e.g. due to the invalid configuration
+	var exitErr error
+	err := wait.For(ctx, func(attemptCtx context.Context) error {
+		start := time.Now()
+		err := probe(attemptCtx)
+		logger.LogVerbose("health check duration", time.Since(start))
+		if err != nil && !isContainerRunning(ctx, backend, contID) {
+			exitErr = containerExitedError(ctx, backend, contID)
+			cancel()
+		}
+		return err
+	}, waitOpts)
+	if exitErr != nil {
+		return fmt.Errorf("failed to wait for the database to be operational: %w", exitErr)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to wait for the database to be operational: %w", err)
+	}
+	return nil
+}
+
+// ExecProbe creates a readiness probe, which runs the provided command inside
+// of the container, treating zero exit code as success
+func ExecProbe(backend Backend, contID string, cmd ...string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		_, err := backend.Exec(ctx, contID, cmd...)
+		return err
+	}
+}
+
+// containerExitedError creates an error with the last lines of the container's
+// logs, as they usually contain the reason, e.g. a failed init script
+func containerExitedError(ctx context.Context, backend Backend, contID string) error {
+	out, err := backend.Logs(ctx, contID, 20)
+	if err != nil {
+		return fmt.Errorf("container exited unexpectedly, check its logs with `%s logs`", backend.Runtime().Name)
+	}
+	return fmt.Errorf("container exited unexpectedly, last log lines:\n%s", strings.TrimSpace(out))
+}
+
+func isContainerRunning(ctx context.Context, backend Backend, contID string) bool {
+	running, err := backend.IsRunning(ctx, contID)
+	if err != nil {
+		// can't tell for sure, so letting the probe retry
+		return true
+	}
+	return running
+}
@@ -0,0 +1,85 @@
+package dbcreator
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Runtime is a container engine with a docker-compatible CLI
+type Runtime struct {
+	// name of the runtime and its CLI executable
+	Name string
+	// short image names, e.g. "postgres", must be fully qualified, as they
+	// can't be resolved non-interactively (podman with unconfigured
+	// unqualified-search-registries)
+	QualifiedImages bool
+	// bind mounts require `--userns=keep-id`, so files in the host directory
+	// stay owned by the current user in rootless mode
+	KeepIDForBindMounts bool
+	// ports can be published on IPv6 host addresses, e.g. [::1]:5432; rootless
+	// port forwarders often lack IPv6 support
+	IPv6PortBindings bool
+}
+
+var (
+	Docker  = Runtime{Name: "docker", IPv6PortBindings: true}
+	Podman  = Runtime{Name: "podman", QualifiedImages: true, KeepIDForBindMounts: true}
+	Nerdctl = Runtime{Name: "nerdctl"}
+)
+
+// Runtimes are all of the supported runtimes, in order of the auto-detection
+var Runtimes = [...]Runtime{Docker, Podman, Nerdctl}
+
+// RuntimeByName returns the supported runtime by its name
+func RuntimeByName(name string) (Runtime, error) {
+	names := make([]string, len(Runtimes))
+	for i, rt := range Runtimes {
+		if rt.Name == name {
+			return rt, nil
+		}
+		names[i] = rt.Name
+	}
+	return Runtime{}, fmt.Errorf("unknown runtime '%s', must be one of: %s", name, strings.Join(names, ", "))
+}
+
+// DetectRuntime returns the first of the supported runtimes, available in PATH
+func DetectRuntime() (Runtime, error) {
+	for _, rt := range Runtimes {
+		if rt.IsAvailable() {
+			return rt, nil
+		}
+	}
+	return Runtime{}, fmt.Errorf("none of the container runtimes (docker, podman or nerdctl) found in PATH")
+}
+
+// IsAvailable reports whether the runtime's CLI is available in PATH
+func (rt Runtime) IsAvailable() bool {
+	_, err := exec.LookPath(rt.Name)
+	return err == nil
+}
+
+// Image returns the image reference with the tag, fully qualifying the image
+// name if the runtime requires it, e.g. "docker.io/library/postgres:16"
+func (rt Runtime) Image(image string, tag string) string {
+	return fmt.Sprintf("%s:%s", rt.ImageName(image), tag)
+}
+
+// ImageName returns the image name, fully qualified if the runtime requires it
+func (rt Runtime) ImageName(image string) string {
+	if !rt.QualifiedImages {
+		return image
+	}
+	return qualifyImage(image)
+}
+
+func qualifyImage(image string) string {
+	domain, rest, found := strings.Cut(image, "/")
+	if found && (strings.ContainsAny(domain, ".:") || domain == "localhost") {
+		return image
+	}
+	if !found {
+		return "docker.io/library/" + image
+	}
+	return "docker.io/" + domain + "/" + rest
+}
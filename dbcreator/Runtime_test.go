@@ -0,0 +1,36 @@
+package dbcreator
+
+import "testing"
+
+func TestRuntimeImage(t *testing.T) {
+	cases := [...]struct {
+		runtime Runtime
+		image   string
+		want    string
+	}{
+		{Docker, "postgres", "postgres:16"},
+		{Podman, "postgres", "docker.io/library/postgres:16"},
+		{Podman, "bitnami/postgresql", "docker.io/bitnami/postgresql:16"},
+		{Podman, "mcr.microsoft.com/mssql/server", "mcr.microsoft.com/mssql/server:16"},
+		{Podman, "localhost/postgres", "localhost/postgres:16"},
+		{Podman, "registry:5000/postgres", "registry:5000/postgres:16"},
+		{Nerdctl, "postgres", "postgres:16"},
+	}
+	for _, tt := range cases {
+		t.Run(tt.runtime.Name+" "+tt.image, func(t *testing.T) {
+			if got := tt.runtime.Image(tt.image, "16"); got != tt.want {
+				t.Errorf("Unexpected value, want %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRuntimeByName(t *testing.T) {
+	rt, err := RuntimeByName("podman")
+	if err != nil || rt != Podman {
+		t.Errorf("Unexpected result: %v, %v", rt, err)
+	}
+	if _, err := RuntimeByName("lxc"); err == nil {
+		t.Error("Expected an error for unknown runtime")
+	}
+}
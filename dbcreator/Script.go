@@ -0,0 +1,191 @@
+package dbcreator
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ScriptDialect is the shell language of the dry run output
+type ScriptDialect string
+
+const (
+	Bash       ScriptDialect = "bash"
+	PowerShell ScriptDialect = "powershell"
+)
+
+// ScriptDialects are all of the supported dialects, the first one is default
+var ScriptDialects = [...]ScriptDialect{Bash, PowerShell}
+
+// containerIDPlaceholder is returned as the container ID in dry run mode and
+// is replaced with the variable, holding the ID, in the script
+const containerIDPlaceholder = "\x00container-id\x00"
+
+// Script writes the commands of a dry run as a runnable shell script, so the
+// container ID is captured into a variable and the readiness probe is waited
+// for in a loop
+type Script struct {
+	dialect ScriptDialect
+	started bool
+	// commands of the readiness probe, collected by Wait
+	probe   [][]string
+	inProbe bool
+}
+
+// NewScript creates a script of the dialect
+func NewScript(dialect ScriptDialect) *Script {
+	return &Script{dialect: dialect}
+}
+
+// Command writes the command, exiting the script if it fails
+func (s *Script) Command(out io.Writer, name string, args ...string) {
+	if s.inProbe {
+		s.probe = append(s.probe, append([]string{name}, args...))
+		return
+	}
+	s.writeHeader(out)
+	cmd := s.cmdString(name, args...)
+	if s.dialect == PowerShell {
+		fmt.Fprintf(out, "%s\nif ($LASTEXITCODE -ne 0) { exit $LASTEXITCODE }\n", cmd)
+		return
+	}
+	fmt.Fprintln(out, cmd)
+}
+
+// CaptureContainerID writes the command, storing its output as the container
+// ID, and returns the placeholder to use instead of the ID in the following
+// commands
+func (s *Script) CaptureContainerID(out io.Writer, name string, args ...string) string {
+	s.writeHeader(out)
+	cmd := s.cmdString(name, args...)
+	if s.dialect == PowerShell {
+		fmt.Fprintf(out, "$ContainerId = %s\nif ($LASTEXITCODE -ne 0) { exit $LASTEXITCODE }\n", cmd)
+	} else {
+		fmt.Fprintf(out, "CONTAINER_ID=$(%s)\n", cmd)
+	}
+	return containerIDPlaceholder
+}
+
+// Wait writes a loop, retrying the commands, run by probe, until they all
+// succeed or the timeout is reached
+func (s *Script) Wait(out io.Writer, timeout time.Duration, probe func()) {
+	s.inProbe = true
+	probe()
+	s.inProbe = false
+	commands := s.probe
+	s.probe = nil
+	if len(commands) == 0 {
+		return
+	}
+
+	s.writeHeader(out)
+	seconds := int(timeout.Round(time.Second) / time.Second)
+	if s.dialect == PowerShell {
+		fmt.Fprintf(out, "$Deadline = (Get-Date).AddSeconds(%d)\n", seconds)
+		fmt.Fprintln(out, "while ($true) {")
+		for i, cmd := range commands {
+			line := s.cmdString(cmd[0], cmd[1:]...) + " *> $null"
+			if i > 0 {
+				line = "if ($LASTEXITCODE -eq 0) { " + line + " }"
+			}
+			fmt.Fprintf(out, "  %s\n", line)
+		}
+		fmt.Fprintln(out, "  if ($LASTEXITCODE -eq 0) { break }")
+		fmt.Fprintln(out, "  if ((Get-Date) -gt $Deadline) { Write-Error 'Timed out waiting for the database'; exit 1 }")
+		fmt.Fprintln(out, "  Start-Sleep -Seconds 1")
+		fmt.Fprintln(out, "}")
+		return
+	}
+	conditions := make([]string, len(commands))
+	for i, cmd := range commands {
+		conditions[i] = s.cmdString(cmd[0], cmd[1:]...) + " >/dev/null 2>&1"
+	}
+	fmt.Fprintf(out, "deadline=$((SECONDS + %d))\n", seconds)
+	fmt.Fprintf(out, "until %s; do\n", strings.Join(conditions, " && "))
+	fmt.Fprintln(out, "  if [ \"$SECONDS\" -ge \"$deadline\" ]; then echo 'Timed out waiting for the database' >&2; exit 1; fi")
+	fmt.Fprintln(out, "  sleep 1")
+	fmt.Fprintln(out, "done")
+}
+
+func (s *Script) writeHeader(out io.Writer) {
+	if s.started {
+		return
+	}
+	s.started = true
+	// PowerShell is left with the default error action, as with "Stop"
+	// Windows PowerShell throws on stderr output of the redirected probes, so
+	// exit codes of the commands are checked instead
+	if s.dialect == PowerShell {
+		return
+	}
+	fmt.Fprintln(out, "#!/usr/bin/env bash")
+	fmt.Fprintln(out, "set -euo pipefail")
+}
+
+func (s *Script) cmdString(name string, args ...string) string {
+	var sb strings.Builder
+	sb.WriteString(name)
+	for _, arg := range args {
+		sb.WriteString(" ")
+		sb.WriteString(s.quote(arg))
+	}
+	return sb.String()
+}
+
+// quote quotes the argument, replacing the container ID placeholder with the
+// variable reference
+func (s *Script) quote(arg string) string {
+	if s.dialect == PowerShell {
+		if !strings.Contains(arg, containerIDPlaceholder) {
+			return QuotePowerShell(arg)
+		}
+		// expandable string, escaping everything except the variable
+		parts := strings.Split(arg, containerIDPlaceholder)
+		for i, part := range parts {
+			parts[i] = powerShellExpandableEscaper.Replace(part)
+		}
+		return `"` + strings.Join(parts, "${ContainerId}") + `"`
+	}
+
+	if !strings.Contains(arg, containerIDPlaceholder) {
+		return Quote(arg)
+	}
+	var sb strings.Builder
+	for i, part := range strings.Split(arg, containerIDPlaceholder) {
+		if i > 0 {
+			sb.WriteString(`"$CONTAINER_ID"`)
+		}
+		if part != "" {
+			sb.WriteString(Quote(part))
+		}
+	}
+	return sb.String()
+}
+
+// PowerShell treats typographic quotes the same way as the ASCII ones, so
+// they're escaped too
+var (
+	powerShellExpandableEscaper = strings.NewReplacer(
+		"`", "``", "$", "`$", `"`, "`\"", "\u201c", "`\u201c", "\u201d", "`\u201d", "\u201e", "`\u201e",
+	)
+	powerShellVerbatimEscaper = strings.NewReplacer(
+		"'", "''", "\u2018", "\u2018\u2018", "\u2019", "\u2019\u2019", "\u201a", "\u201a\u201a", "\u201b", "\u201b\u201b",
+	)
+)
+
+// arguments, which PowerShell passes to native commands as is, without
+// treating them as expressions, arrays or splatting; arguments starting with
+// a dash are split by older versions at dots and colons
+var powerShellSafePattern = regexp.MustCompile(`^(-[\w=-]*|[\w./:][\w./:=-]*)$`)
+
+// QuotePowerShell quotes a PowerShell argument. Arguments containing double
+// quotes are passed to native commands correctly only since PowerShell 7.3.
+func QuotePowerShell(arg string) string {
+	if powerShellSafePattern.MatchString(arg) {
+		return arg
+	}
+	// single-quoted strings are verbatim, except for the doubled quotes
+	return "'" + powerShellVerbatimEscaper.Replace(arg) + "'"
+}
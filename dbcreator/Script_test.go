@@ -0,0 +1,106 @@
+package dbcreator
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/religiosa1/init-docker-db/wait"
+)
+
+func TestQuotePowerShell(t *testing.T) {
+	tests := []struct {
+		arg  string
+		want string
+	}{
+		{"postgres:16", "postgres:16"},
+		{"--userns=keep-id", "--userns=keep-id"},
+		{"", "''"},
+		{"[::1]:5432:5432", "'[::1]:5432:5432'"},
+		{"PASSWORD=it's $secret", "'PASSWORD=it''s $secret'"},
+		{"a,b", "'a,b'"},
+		{"@args", "'@args'"},
+		{"-Dfoo.bar", "'-Dfoo.bar'"},
+		{"it’s", "'it’’s'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			if got := QuotePowerShell(tt.arg); got != tt.want {
+				t.Errorf("Unexpected value, want %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestScriptQuote(t *testing.T) {
+	tests := []struct {
+		dialect ScriptDialect
+		arg     string
+		want    string
+	}{
+		{Bash, "", "''"},
+		{Bash, "postgres:16", "postgres:16"},
+		{Bash, containerIDPlaceholder, `"$CONTAINER_ID"`},
+		{Bash, containerIDPlaceholder + ":/tmp/a b", `"$CONTAINER_ID"':/tmp/a b'`},
+		{PowerShell, "", "''"},
+		{PowerShell, containerIDPlaceholder + ":/tmp", `"${ContainerId}:/tmp"`},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.dialect)+" "+tt.arg, func(t *testing.T) {
+			if got := NewScript(tt.dialect).quote(tt.arg); got != tt.want {
+				t.Errorf("Unexpected value, want %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCLIBackendDryRunScript(t *testing.T) {
+	spec := ContainerSpec{Name: "foo", Image: "postgres", Tag: "16", Env: []string{"POSTGRES_PASSWORD=pa$$"}}
+	tests := []struct {
+		dialect ScriptDialect
+		want    []string
+	}{
+		{Bash, []string{
+			"set -euo pipefail\n",
+			"CONTAINER_ID=$(docker run --name foo -e 'POSTGRES_PASSWORD=pa$$' -d postgres:16)\n",
+			"deadline=$((SECONDS + 30))\n",
+			"until docker exec \"$CONTAINER_ID\" pg_isready >/dev/null 2>&1; do\n",
+			"docker cp init.sql \"$CONTAINER_ID\":/tmp/init.sql\n",
+		}},
+		{PowerShell, []string{
+			"$ContainerId = docker run --name foo -e 'POSTGRES_PASSWORD=pa$$' -d postgres:16\nif ($LASTEXITCODE -ne 0) { exit $LASTEXITCODE }\n",
+			"$Deadline = (Get-Date).AddSeconds(30)\n",
+			"  docker exec \"${ContainerId}\" pg_isready *> $null\n",
+			"docker cp init.sql \"${ContainerId}:/tmp/init.sql\"\nif ($LASTEXITCODE -ne 0) { exit $LASTEXITCODE }\n",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.dialect), func(t *testing.T) {
+			var out bytes.Buffer
+			backend := NewCLIBackend(NewShell(true, false).WithOutput(&out).WithScriptDialect(tt.dialect))
+			ctx := context.Background()
+			logger := NewProgressLogger(ctx, false, true)
+
+			contID, err := backend.Run(ctx, spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			probe := ExecProbe(backend, contID, "pg_isready")
+			if err := WaitForReady(ctx, backend, contID, 30*time.Second, &logger, probe, wait.Opts{}); err != nil {
+				t.Fatal(err)
+			}
+			if err := backend.CopyTo(ctx, contID, "init.sql", "/tmp/init.sql"); err != nil {
+				t.Fatal(err)
+			}
+
+			got := out.String()
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("Expected %q in the script, got\n%s", want, got)
+				}
+			}
+		})
+	}
+}
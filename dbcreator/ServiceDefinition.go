@@ -0,0 +1,13 @@
+package dbcreator
+
+// ServiceDefinition is a declarative description of the database container,
+// equivalent to what the creator runs. It's used both for running the
+// container and for exporting it, e.g. into a compose file.
+type ServiceDefinition struct {
+	Spec ContainerSpec
+	// command checking the database readiness inside of the container
+	HealthCheck []string
+	// one-off container performing the database setup, once the database is
+	// healthy, for images without their own init entrypoint (MsSQL)
+	Init *ContainerSpec
+}
@@ -2,6 +2,7 @@ package dbcreator
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"os"
@@ -15,84 +16,125 @@ func DockerEnv(key string, value string) string {
 	return fmt.Sprintf("%s=%s", key, value)
 }
 
-// Shell is a child process runner with output verbosity flag
+// Shell is a child process runner with output verbosity flag. Child processes
+// are killed, when the provided context is cancelled.
 type Shell struct {
 	dryRun  bool
 	verbose bool
+	out     io.Writer
+	runtime Runtime
+	// script, the commands are written into in dry run mode
+	script *Script
 }
 
-// NewShell creates a  new shell instance
+// NewShell creates a  new shell instance. In dry run mode commands are written
+// as a bash script.
 func NewShell(dryRun bool, verbose bool) Shell {
-	return Shell{
+	sh := Shell{
 		dryRun:  dryRun,
 		verbose: verbose,
+		out:     os.Stdout,
+		runtime: Docker,
 	}
+	if dryRun {
+		sh.script = NewScript(Bash)
+	}
+	return sh
 }
 
-// RunWithOutput runs a new shell instance capturing it's stdout as a return value
-func (sh Shell) RunWithOutput(name string, args ...string) (string, error) {
-	if sh.dryRun || sh.verbose {
-		fmt.Println(makeShellCmdString(name, args...))
+// WithScriptDialect returns a copy of the shell, which writes the dry run
+// script in the provided dialect
+func (sh Shell) WithScriptDialect(dialect ScriptDialect) Shell {
+	if sh.dryRun {
+		sh.script = NewScript(dialect)
 	}
+	return sh
+}
+
+// WithOutput returns a copy of the shell, which writes commands and child
+// processes' stdout to the provided writer instead of Stdout
+func (sh Shell) WithOutput(out io.Writer) Shell {
+	sh.out = out
+	return sh
+}
+
+// WithRuntime returns a copy of the shell, which uses the provided container
+// runtime instead of docker
+func (sh Shell) WithRuntime(runtime Runtime) Shell {
+	sh.runtime = runtime
+	return sh
+}
+
+// Runtime returns the container runtime, used by the shell
+func (sh Shell) Runtime() Runtime {
+	return sh.runtime
+}
+
+// RunWithOutput runs a new shell instance capturing it's stdout as a return value
+func (sh Shell) RunWithOutput(ctx context.Context, name string, args ...string) (string, error) {
 	if sh.dryRun {
+		sh.script.Command(sh.out, name, args...)
 		return "", nil
 	}
-	cmd := exec.Command(name, args...)
+	sh.logCommand(name, args...)
+	cmd := exec.CommandContext(ctx, name, args...)
 	out, err := cmd.CombinedOutput()
 	return string(out), err
 }
 
-// RunWithTeeOutput runs a child process, streaming its output to Stdout/Stderr while also capturing it as a return value
-func (sh Shell) RunWithTeeOutput(name string, args ...string) (string, error) {
-	if sh.dryRun || sh.verbose {
-		fmt.Println(makeShellCmdString(name, args...))
-	}
+// RunWithTeeOutput runs a child process, streaming its output to Stdout/Stderr while also capturing its stdout as a return value
+func (sh Shell) RunWithTeeOutput(ctx context.Context, name string, args ...string) (string, error) {
 	if sh.dryRun {
+		sh.script.Command(sh.out, name, args...)
 		return "", nil
 	}
-	cmd := exec.Command(name, args...)
+	sh.logCommand(name, args...)
+	cmd := exec.CommandContext(ctx, name, args...)
 
-	var outBuf, errBuf bytes.Buffer
-	cmd.Stdout = io.MultiWriter(os.Stdout, &outBuf)
-	cmd.Stderr = io.MultiWriter(os.Stderr, &errBuf)
+	// Only stdout is captured, as stderr can contain unrelated progress
+	// messages, e.g. docker image pulling status
+	var outBuf bytes.Buffer
+	cmd.Stdout = io.MultiWriter(sh.out, &outBuf)
+	cmd.Stderr = os.Stderr
 
 	err := cmd.Run()
-
-	// Combine stdout and stderr for the return value, similar to CombinedOutput
-	combined := outBuf.String() + errBuf.String()
-	return combined, err
+	return outBuf.String(), err
 }
 
 // RunSilent runs a child process, printing its outputs to Stdout/Stderr only in the verbose mode
-func (sh Shell) RunSilent(name string, args ...string) error {
-	if sh.dryRun || sh.verbose {
-		fmt.Println(makeShellCmdString(name, args...))
-	}
+func (sh Shell) RunSilent(ctx context.Context, name string, args ...string) error {
 	if sh.dryRun {
+		sh.script.Command(sh.out, name, args...)
 		return nil
 	}
-	cmd := exec.Command(name, args...)
+	sh.logCommand(name, args...)
+	cmd := exec.CommandContext(ctx, name, args...)
 	if !sh.verbose {
-		cmd.Stdout = os.Stdout
+		cmd.Stdout = sh.out
 		cmd.Stderr = os.Stderr
 	}
 	return cmd.Run()
 }
 
 // Run a child process, printing its output to Stdout/Stderr
-func (sh Shell) Run(name string, args ...string) error {
-	if sh.dryRun || sh.verbose {
-		fmt.Println(makeShellCmdString(name, args...))
-	}
+func (sh Shell) Run(ctx context.Context, name string, args ...string) error {
 	if sh.dryRun {
+		sh.script.Command(sh.out, name, args...)
 		return nil
 	}
-	cmd := exec.Command(name, args...)
-	cmd.Stdout = os.Stdout
+	sh.logCommand(name, args...)
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdout = sh.out
 	cmd.Stderr = os.Stderr
 	return cmd.Run()
 }
 
+func (sh Shell) logCommand(name string, args ...string) {
+	if sh.verbose {
+		_, _ = fmt.Fprintln(sh.out, makeShellCmdString(name, args...))
+	}
+}
+
 var pattern *regexp.Regexp
 
 // Quote a shell argument
@@ -100,7 +142,7 @@ func Quote(cmd string) string {
 	if pattern == nil {
 		pattern = regexp.MustCompile(`[^\w@%+=:,./-]`)
 	}
-	if pattern.MatchString(cmd) {
+	if cmd == "" || pattern.MatchString(cmd) {
 		return "'" + strings.ReplaceAll(cmd, "'", "'\"'\"'") + "'"
 	}
 
@@ -0,0 +1,149 @@
+package dbcreator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Transaction tracks resources created during the container creation, so
+// they can be removed if any of the creation steps fails or is interrupted.
+// It's safe for concurrent use.
+type Transaction struct {
+	backend    Backend
+	mu         sync.Mutex
+	step       string
+	containers []string
+	volumes    []string
+	finished   bool
+}
+
+// StepError is an error of a single creation step
+type StepError struct {
+	Step string
+	Err  error
+}
+
+func (e *StepError) Error() string {
+	return fmt.Sprintf("failed at step '%s': %s", e.Step, e.Err)
+}
+
+func (e *StepError) Unwrap() error {
+	return e.Err
+}
+
+// NewTransaction creates a new transaction, removing resources with the backend
+func NewTransaction(backend Backend) *Transaction {
+	return &Transaction{backend: backend}
+}
+
+// Step runs a single creation step, wrapping its error into StepError
+func (tx *Transaction) Step(name string, fn func() error) error {
+	tx.mu.Lock()
+	tx.step = name
+	tx.mu.Unlock()
+
+	if err := fn(); err != nil {
+		var stepErr *StepError
+		if errors.As(err, &stepErr) {
+			return err
+		}
+		return &StepError{Step: name, Err: err}
+	}
+	return nil
+}
+
+// CurrentStep returns the name of the last started step
+func (tx *Transaction) CurrentStep() string {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+	return tx.step
+}
+
+// AddContainer registers a created container to be removed on rollback
+func (tx *Transaction) AddContainer(contID string) {
+	if contID == "" {
+		return
+	}
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+	tx.containers = append(tx.containers, contID)
+}
+
+// TrackVolume registers the named volume to be removed on rollback, if it
+// doesn't exist yet and will be created by docker alongside the container.
+// Bind mounts and pre-existing volumes are never removed.
+func (tx *Transaction) TrackVolume(ctx context.Context, volume string) {
+	if volume == "" || tx.backend.DryRun() {
+		return
+	}
+	if _, isBindMount, _ := VolumeSource(volume); isBindMount {
+		return
+	}
+	if exists, err := tx.backend.VolumeExists(ctx, volume); exists || err != nil {
+		return
+	}
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+	tx.volumes = append(tx.volumes, volume)
+}
+
+// Commit marks the creation as successful, so resources are kept
+func (tx *Transaction) Commit() {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+	tx.finished = true
+}
+
+// Rollback force removes the created containers with their anonymous volumes
+// and the created named volumes. Does nothing, if the transaction is already
+// committed or rolled back. Returns the removed resources. As it's usually
+// called after the creation is cancelled, ctx shouldn't be the same context.
+func (tx *Transaction) Rollback(ctx context.Context) ([]string, error) {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+	if tx.finished || tx.backend.DryRun() {
+		return nil, nil
+	}
+	tx.finished = true
+
+	var removed []string
+	var errs []error
+	for _, contID := range tx.containers {
+		if err := tx.backend.Remove(ctx, contID); err != nil {
+			errs = append(errs, fmt.Errorf("error removing container '%s': %w", contID, err))
+			continue
+		}
+		removed = append(removed, "container "+shortID(contID))
+	}
+	for _, volume := range tx.volumes {
+		if err := tx.backend.RemoveVolume(ctx, volume); err != nil {
+			errs = append(errs, fmt.Errorf("error removing volume '%s': %w", volume, err))
+			continue
+		}
+		removed = append(removed, "volume "+volume)
+	}
+	return removed, errors.Join(errs...)
+}
+
+// Resources returns the created resources, tracked by the transaction
+func (tx *Transaction) Resources() []string {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+	resources := make([]string, 0, len(tx.containers)+len(tx.volumes))
+	for _, contID := range tx.containers {
+		resources = append(resources, "container "+shortID(contID))
+	}
+	for _, volume := range tx.volumes {
+		resources = append(resources, "volume "+volume)
+	}
+	return resources
+}
+
+func shortID(contID string) string {
+	if len(contID) > 12 {
+		return contID[:12]
+	}
+	return contID
+}
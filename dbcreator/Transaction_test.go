@@ -0,0 +1,50 @@
+package dbcreator
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestTransactionStep(t *testing.T) {
+	tx := NewTransaction(NewCLIBackend(NewShell(true, false)))
+	errFailed := errors.New("failed")
+
+	if err := tx.Step("first", func() error { return nil }); err != nil {
+		t.Fatal(err)
+	}
+	err := tx.Step("outer", func() error {
+		return tx.Step("inner", func() error { return errFailed })
+	})
+
+	var stepErr *StepError
+	if !errors.As(err, &stepErr) {
+		t.Fatalf("Expected StepError, got %v", err)
+	}
+	if stepErr.Step != "inner" {
+		t.Errorf("Expected the innermost step to be reported, got %s", stepErr.Step)
+	}
+	if !errors.Is(err, errFailed) {
+		t.Errorf("Expected the step's error to be wrapped, got %v", err)
+	}
+	if tx.CurrentStep() != "inner" {
+		t.Errorf("Unexpected current step %s", tx.CurrentStep())
+	}
+}
+
+func TestTransactionResources(t *testing.T) {
+	tx := NewTransaction(NewCLIBackend(NewShell(true, false)))
+	tx.AddContainer("")
+	tx.AddContainer("0123456789abcdef")
+	tx.TrackVolume(context.Background(), "pgdata") // dry run, so can't know if the volume exists
+
+	resources := tx.Resources()
+	if len(resources) != 1 || resources[0] != "container 0123456789ab" {
+		t.Errorf("Unexpected resources %v", resources)
+	}
+
+	removed, err := tx.Rollback(context.Background())
+	if err != nil || len(removed) != 0 {
+		t.Errorf("Expected no rollback in dry run, got %v %v", removed, err)
+	}
+}
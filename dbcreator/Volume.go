@@ -0,0 +1,43 @@
+package dbcreator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// VolumeSource resolves the volume argument into a docker mount source.
+// Anything that looks like a path (contains a path separator or starts with
+// a dot or tilde) is treated as a host directory bind mount and made absolute,
+// as docker requires it. Everything else is treated as a named volume.
+func VolumeSource(volume string) (source string, isBindMount bool, err error) {
+	if !strings.ContainsAny(volume, `/\`) && !strings.HasPrefix(volume, ".") && !strings.HasPrefix(volume, "~") {
+		return volume, false, nil
+	}
+	if volume == "~" || strings.HasPrefix(volume, "~/") || strings.HasPrefix(volume, `~\`) {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", true, fmt.Errorf("error resolving home directory: %w", err)
+		}
+		volume = filepath.Join(home, volume[1:])
+	}
+	abs, err := filepath.Abs(volume)
+	if err != nil {
+		return "", true, fmt.Errorf("error resolving volume path: %w", err)
+	}
+	return abs, true, nil
+}
+
+// CreateVolumeMounts creates mounts of the volume at the data path inside of
+// the container. Returns nil, if volume is empty.
+func CreateVolumeMounts(volume string, dataPath string) ([]Mount, error) {
+	if volume == "" {
+		return nil, nil
+	}
+	source, _, err := VolumeSource(volume)
+	if err != nil {
+		return nil, err
+	}
+	return []Mount{{Source: source, Target: dataPath}}, nil
+}
@@ -0,0 +1,60 @@
+package dbcreator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestVolumeSource(t *testing.T) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := [...]struct {
+		input       string
+		source      string
+		isBindMount bool
+	}{
+		{"pgdata", "pgdata", false},
+		{"my_volume.1", "my_volume.1", false},
+		{"./data", filepath.Join(cwd, "data"), true},
+		{"data/pg", filepath.Join(cwd, "data/pg"), true},
+		{"/var/data", "/var/data", true},
+		{"~/data", filepath.Join(home, "data"), true},
+	}
+	for _, tt := range cases {
+		t.Run(tt.input, func(t *testing.T) {
+			source, isBindMount, err := VolumeSource(tt.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if source != tt.source || isBindMount != tt.isBindMount {
+				t.Errorf("Unexpected value, want %s %v, got %s %v", tt.source, tt.isBindMount, source, isBindMount)
+			}
+		})
+	}
+}
+
+func TestCreateVolumeMounts(t *testing.T) {
+	t.Run("returns nothing for an empty volume", func(t *testing.T) {
+		mounts, err := CreateVolumeMounts("", "/data")
+		if err != nil || mounts != nil {
+			t.Errorf("Unexpected result: %v, %v", mounts, err)
+		}
+	})
+	t.Run("mounts the volume at the data path", func(t *testing.T) {
+		mounts, err := CreateVolumeMounts("pgdata", "/var/lib/postgresql/data")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(mounts) != 1 || mounts[0].String() != "pgdata:/var/lib/postgresql/data" || mounts[0].IsBind() {
+			t.Errorf("Unexpected value: %v", mounts)
+		}
+	})
+}
@@ -0,0 +1,35 @@
+package dbcreator
+
+import (
+	"fmt"
+	"time"
+)
+
+// Labels stamped on every container created by the tool, so it can be found
+// again by the list/destroy commands
+const (
+	labelPrefix = "io.github.religiosa1.init-docker-db."
+
+	LabelEngine    = labelPrefix + "engine"
+	LabelDatabase  = labelPrefix + "database"
+	LabelUser      = labelPrefix + "user"
+	LabelVersion   = labelPrefix + "version"
+	LabelCreatedAt = labelPrefix + "created-at"
+)
+
+// CreateLabels creates a list of labels in KEY=VALUE format for the container
+// of the specified engine type
+func CreateLabels(engine string, opts CreateOptions) []string {
+	labels := [...][2]string{
+		{LabelEngine, engine},
+		{LabelDatabase, opts.Database},
+		{LabelUser, opts.User},
+		{LabelVersion, opts.ToolVersion},
+		{LabelCreatedAt, time.Now().UTC().Format(time.RFC3339)},
+	}
+	result := make([]string, len(labels))
+	for i, label := range labels {
+		result[i] = fmt.Sprintf("%s=%s", label[0], label[1])
+	}
+	return result
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/huh"
+	"github.com/religiosa1/init-docker-db/dbcreator"
+	"github.com/religiosa1/init-docker-db/managed"
+)
+
+type DestroyArgs struct {
+	Names          []string `arg:"" optional:"" name:"containerName" help:"names of the containers to be removed"`
+	All            bool     `help:"remove all of the containers created by init-docker-db"`
+	OlderThan      string   `placeholder:"AGE" help:"remove only containers older than the specified age, e.g. 7d, 12h or 30m"`
+	NonInteractive bool     `short:"n" help:"do not ask for the confirmation"`
+	Dry            DryRun   `short:"D" help:"dry run, printing docker commands to stdout as a bash script (or powershell with --dry=powershell), without actually running them"`
+	Verbose        bool     `short:"v" help:"run with verbose logging"`
+}
+
+func runDestroy(ctx context.Context, args DestroyArgs) {
+	out := io.Writer(os.Stdout)
+	if isJSONOutput() {
+		out = os.Stderr
+	}
+	if err := checkConfirmationMode(args); err != nil {
+		exitWithError(ExitStatusFailedToDestroyContainers, err)
+	}
+	// containers are listed even in dry run mode, so runtime is required
+	backend := getBackend(ctx, "", args.Verbose, out, false)
+
+	containers, err := selectContainersToDestroy(ctx, backend, args)
+	if err != nil {
+		exitWithError(ExitStatusFailedToDestroyContainers, err)
+	}
+	if len(containers) == 0 && !isJSONOutput() {
+		fmt.Println("No matching containers found")
+		return
+	}
+
+	if !args.NonInteractive && !args.Dry.Enabled() {
+		confirmed, err := confirmDestroy(containers)
+		if err != nil {
+			exitWithError(ExitStatusFailedToDestroyContainers, err)
+		}
+		if !confirmed {
+			return
+		}
+	}
+
+	if args.Dry.Enabled() {
+		backend = getBackend(ctx, args.Dry, args.Verbose, out, false)
+	}
+	err = managed.Remove(ctx, backend, containers)
+	if err != nil {
+		exitWithError(ExitStatusFailedToDestroyContainers, err)
+	}
+
+	if isJSONOutput() {
+		printJSON(destroyResult{Removed: makeContainerResults(containers), DryRun: args.Dry.Enabled()})
+	} else if !args.Dry.Enabled() {
+		for _, c := range containers {
+			fmt.Println(c.Name)
+		}
+	}
+}
+
+// checkConfirmationMode rejects JSON output without --non-interactive flag:
+// prompts would break machine-readable output, while skipping the
+// confirmation implicitly, e.g. due to the output set in the environment, is
+// dangerous
+func checkConfirmationMode(args DestroyArgs) error {
+	if isJSONOutput() && !args.NonInteractive && !args.Dry.Enabled() {
+		return errors.New("destroy requires a confirmation, which can't be asked with JSON output; pass -n/--non-interactive to skip it")
+	}
+	return nil
+}
+
+func selectContainersToDestroy(ctx context.Context, backend dbcreator.Backend, args DestroyArgs) ([]managed.Container, error) {
+	if len(args.Names) == 0 && !args.All && args.OlderThan == "" {
+		return nil, errors.New("either container names, --all or --older-than flag must be provided")
+	}
+	if len(args.Names) != 0 && args.All {
+		return nil, errors.New("container names and --all flag are mutually exclusive")
+	}
+	var olderThan time.Duration
+	if args.OlderThan != "" {
+		var err error
+		olderThan, err = parseAge(args.OlderThan)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Listing is always performed, even in dry mode, as we need to know which
+	// containers are created by us
+	containers, err := managed.List(ctx, backend)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(args.Names) != 0 {
+		containers, err = filterByNames(containers, args.Names)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if olderThan != 0 {
+		containers = filterOlderThan(containers, olderThan, time.Now())
+	}
+	return containers, nil
+}
+
+func filterByNames(containers []managed.Container, names []string) ([]managed.Container, error) {
+	result := make([]managed.Container, 0, len(names))
+	for _, name := range names {
+		idx := -1
+		for i, c := range containers {
+			if c.Name == name || c.ID == name {
+				idx = i
+				break
+			}
+		}
+		if idx == -1 {
+			return nil, fmt.Errorf("container '%s' doesn't exist or wasn't created by init-docker-db, refusing to remove it", name)
+		}
+		result = append(result, containers[idx])
+	}
+	return result, nil
+}
+
+func filterOlderThan(containers []managed.Container, age time.Duration, now time.Time) []managed.Container {
+	result := make([]managed.Container, 0, len(containers))
+	for _, c := range containers {
+		// containers without a valid creation label are skipped, as we can't tell their age
+		if !c.CreatedAt.IsZero() && now.Sub(c.CreatedAt) > age {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// parseAge parses a duration string, additionally supporting the days "d" suffix
+func parseAge(age string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(age, "d"); ok {
+		n, err := strconv.ParseUint(days, 10, 32)
+		if err != nil || n == 0 {
+			return 0, fmt.Errorf("invalid age value '%s'", age)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(age)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid age value '%s'", age)
+	}
+	return d, nil
+}
+
+func confirmDestroy(containers []managed.Container) (bool, error) {
+	names := make([]string, len(containers))
+	for i, c := range containers {
+		names[i] = c.Name
+	}
+	var confirmed bool
+	err := huh.NewForm(huh.NewGroup(
+		huh.NewConfirm().
+			Title(fmt.Sprintf("Remove %d container(s) and their anonymous volumes?", len(containers))).
+			Description(strings.Join(names, "\n")).
+			Value(&confirmed),
+	)).
+		WithTheme(theme).
+		Run()
+	return confirmed, err
+}
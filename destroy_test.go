@@ -0,0 +1,100 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/religiosa1/init-docker-db/managed"
+)
+
+func Test_parseAge(t *testing.T) {
+	cases := [...]struct {
+		input  string
+		output time.Duration
+	}{
+		{"7d", 7 * 24 * time.Hour},
+		{"12h", 12 * time.Hour},
+		{"1h30m", 90 * time.Minute},
+	}
+	for _, tt := range cases {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := parseAge(tt.input)
+			if err != nil {
+				t.Error(err)
+			}
+			if got != tt.output {
+				t.Errorf("Unexpected value, want %s, got %s", tt.output, got)
+			}
+		})
+	}
+
+	invalidCases := [...]string{"", "d", "0d", "-1d", "1.5d", "foo", "-5h"}
+	for _, input := range invalidCases {
+		t.Run("invalid value: "+input, func(t *testing.T) {
+			if _, err := parseAge(input); err == nil {
+				t.Error("expected parseAge to throw, but it didn't")
+			}
+		})
+	}
+}
+
+func Test_filterByNames(t *testing.T) {
+	containers := []managed.Container{
+		{ID: "abc123", Name: "foo"},
+		{ID: "def456", Name: "bar"},
+	}
+
+	t.Run("matches containers by name or id", func(t *testing.T) {
+		got, err := filterByNames(containers, []string{"bar", "abc123"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 2 || got[0].Name != "bar" || got[1].Name != "foo" {
+			t.Errorf("Unexpected result: %+v", got)
+		}
+	})
+
+	t.Run("refuses to work with unmanaged containers", func(t *testing.T) {
+		_, err := filterByNames(containers, []string{"foo", "baz"})
+		if err == nil {
+			t.Error("expected filterByNames to throw, but it didn't")
+		}
+	})
+}
+
+func Test_filterOlderThan(t *testing.T) {
+	now := time.Date(2025, 11, 20, 10, 0, 0, 0, time.UTC)
+	containers := []managed.Container{
+		{Name: "old", CreatedAt: now.Add(-10 * 24 * time.Hour)},
+		{Name: "new", CreatedAt: now.Add(-time.Hour)},
+		{Name: "unknown"},
+	}
+	got := filterOlderThan(containers, 7*24*time.Hour, now)
+	if len(got) != 1 || got[0].Name != "old" {
+		t.Errorf("Unexpected result: %+v", got)
+	}
+}
+
+func Test_checkConfirmationMode(t *testing.T) {
+	cases := [...]struct {
+		name   string
+		output string
+		args   DestroyArgs
+		err    bool
+	}{
+		{"text output", OutputText, DestroyArgs{}, false},
+		{"json output", OutputJSON, DestroyArgs{}, true},
+		{"json output, non-interactive", OutputJSON, DestroyArgs{NonInteractive: true}, false},
+		{"json output, dry run", OutputJSON, DestroyArgs{Dry: "bash"}, false},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			prev := CLI.Output
+			CLI.Output = tt.output
+			defer func() { CLI.Output = prev }()
+			if err := checkConfirmationMode(tt.args); (err != nil) != tt.err {
+				t.Errorf("Unexpected result, want error: %v, got: %v", tt.err, err)
+			}
+		})
+	}
+}
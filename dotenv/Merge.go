@@ -0,0 +1,96 @@
+// Package dotenv provides an utility for writing values into a dotenv file,
+// preserving any unrelated keys and comments already present in it
+package dotenv
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Entry is a single key-value pair of a dotenv file
+type Entry struct {
+	Key   string
+	Value string
+}
+
+// Merge writes entries into the dotenv file at path, creating it if it doesn't
+// exist. Existing keys are updated in place, new ones are appended to the end
+// of the file.
+func Merge(path string, entries []Entry) error {
+	var content string
+	var perm fs.FileMode = 0o600
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		content = string(data)
+		if stat, err := os.Stat(path); err == nil {
+			perm = stat.Mode().Perm()
+		}
+	case !errors.Is(err, fs.ErrNotExist):
+		return fmt.Errorf("error reading dotenv file: %w", err)
+	}
+
+	if err := os.WriteFile(path, []byte(merge(content, entries)), perm); err != nil {
+		return fmt.Errorf("error writing dotenv file: %w", err)
+	}
+	return nil
+}
+
+var keyRe = regexp.MustCompile(`^(\s*(?:export\s+)?)([A-Za-z_][A-Za-z0-9_.]*)\s*=`)
+
+func merge(content string, entries []Entry) string {
+	written := make(map[string]bool, len(entries))
+	values := make(map[string]string, len(entries))
+	for _, e := range entries {
+		values[e.Key] = e.Value
+	}
+
+	var sb strings.Builder
+	for line := range strings.Lines(content) {
+		matches := keyRe.FindStringSubmatch(line)
+		if matches == nil {
+			sb.WriteString(line)
+			continue
+		}
+		value, ok := values[matches[2]]
+		if !ok {
+			sb.WriteString(line)
+			continue
+		}
+		// preserving "export" prefix and line ending of the original line
+		lineEnding := line[len(strings.TrimRight(line, "\r\n")):]
+		sb.WriteString(matches[1] + matches[2] + "=" + quote(value) + lineEnding)
+		written[matches[2]] = true
+	}
+
+	if sb.Len() > 0 && !strings.HasSuffix(sb.String(), "\n") {
+		sb.WriteString("\n")
+	}
+	for _, e := range entries {
+		if !written[e.Key] {
+			sb.WriteString(e.Key + "=" + quote(e.Value) + "\n")
+			written[e.Key] = true
+		}
+	}
+	return sb.String()
+}
+
+var safeValueRe = regexp.MustCompile(`^[\w@%+=:,./?&-]*$`)
+
+// quote a dotenv value, if it contains any special characters
+func quote(value string) string {
+	if safeValueRe.MatchString(value) {
+		return value
+	}
+	// single quoted values are treated literally by most of the dotenv parsers,
+	// so it's preferable to double quotes, unless value contains a single quote
+	if !strings.ContainsAny(value, "'\n") {
+		return "'" + value + "'"
+	}
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "$", `\$`, "\n", `\n`)
+	return `"` + replacer.Replace(value) + `"`
+}
@@ -0,0 +1,81 @@
+package dotenv
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_merge(t *testing.T) {
+	entries := []Entry{
+		{"DB_HOST", "localhost"},
+		{"DB_PORT", "5432"},
+	}
+
+	t.Run("appends entries to an empty file", func(t *testing.T) {
+		got := merge("", entries)
+		if want := "DB_HOST=localhost\nDB_PORT=5432\n"; want != got {
+			t.Errorf("Values do not match, want '%s', got '%s'", want, got)
+		}
+	})
+
+	t.Run("preserves unrelated keys and comments", func(t *testing.T) {
+		got := merge("# comment\nFOO=bar\n\nBAZ='qux'", entries)
+		if want := "# comment\nFOO=bar\n\nBAZ='qux'\nDB_HOST=localhost\nDB_PORT=5432\n"; want != got {
+			t.Errorf("Values do not match, want '%s', got '%s'", want, got)
+		}
+	})
+
+	t.Run("updates existing keys in place", func(t *testing.T) {
+		got := merge("DB_PORT=1\nFOO=bar\nexport DB_HOST = example.com\r\n", entries)
+		if want := "DB_PORT=5432\nFOO=bar\nexport DB_HOST=localhost\r\n"; want != got {
+			t.Errorf("Values do not match, want '%s', got '%s'", want, got)
+		}
+	})
+
+	t.Run("doesn't treat commented out keys as existing", func(t *testing.T) {
+		got := merge("# DB_PORT=1\n", entries[1:])
+		if want := "# DB_PORT=1\nDB_PORT=5432\n"; want != got {
+			t.Errorf("Values do not match, want '%s', got '%s'", want, got)
+		}
+	})
+}
+
+func Test_quote(t *testing.T) {
+	cases := [...]struct {
+		input  string
+		output string
+	}{
+		{"foo", "foo"},
+		{"", ""},
+		{"postgres://u:p@localhost:5432/db?sslmode=disable", "postgres://u:p@localhost:5432/db?sslmode=disable"},
+		{"pass word", "'pass word'"},
+		{"pa$$#word", "'pa$$#word'"},
+		{`it's "$x"`, `"it's \"\$x\""`},
+		{"multi\nline", `"multi\nline"`},
+	}
+	for _, tt := range cases {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := quote(tt.input); got != tt.output {
+				t.Errorf("Values do not match, want '%s', got '%s'", tt.output, got)
+			}
+		})
+	}
+}
+
+func TestMerge(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	if err := Merge(path, []Entry{{"FOO", "bar"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := Merge(path, []Entry{{"BAZ", "qux"}}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := "FOO=bar\nBAZ=qux\n", string(data); want != got {
+		t.Errorf("Values do not match, want '%s', got '%s'", want, got)
+	}
+}
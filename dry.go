@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/alecthomas/kong"
+	"github.com/religiosa1/init-docker-db/dbcreator"
+)
+
+// DryRun is the --dry flag value: the dialect of the printed script, or empty
+// if dry run is disabled. The flag can be used without a value, as a boolean
+// one, defaulting to bash.
+type DryRun dbcreator.ScriptDialect
+
+func (d DryRun) Enabled() bool {
+	return d != ""
+}
+
+func (d DryRun) Dialect() dbcreator.ScriptDialect {
+	return dbcreator.ScriptDialect(d)
+}
+
+// IsBool allows the flag to be used without a value
+func (d DryRun) IsBool() bool {
+	return true
+}
+
+func (d *DryRun) Decode(ctx *kong.DecodeContext) error {
+	if ctx.Scan.Peek().Type != kong.FlagValueToken {
+		*d = DryRun(dbcreator.ScriptDialects[0])
+		return nil
+	}
+	var value string
+	switch v := ctx.Scan.Pop().Value.(type) {
+	case bool:
+		value = fmt.Sprint(v)
+	case string:
+		value = strings.ToLower(v)
+	default:
+		return fmt.Errorf("expected a script dialect or a bool but got %q (%T)", v, v)
+	}
+	switch value {
+	case "true", "1", "yes":
+		*d = DryRun(dbcreator.ScriptDialects[0])
+		return nil
+	case "false", "0", "no", "":
+		*d = ""
+		return nil
+	}
+	dialects := make([]string, len(dbcreator.ScriptDialects))
+	for i, dialect := range dbcreator.ScriptDialects {
+		if value == string(dialect) {
+			*d = DryRun(dialect)
+			return nil
+		}
+		dialects[i] = string(dialect)
+	}
+	return fmt.Errorf("unknown dry run script dialect '%s', must be one of: %s", value, strings.Join(dialects, ", "))
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/religiosa1/init-docker-db/compose"
+	"github.com/religiosa1/init-docker-db/dbcreator"
+	"github.com/religiosa1/init-docker-db/k8s"
+)
+
+type emitResult struct {
+	Format string `json:"format"`
+	// written compose file, empty for formats printed to stdout
+	Path     string   `json:"path,omitempty"`
+	Services []string `json:"services,omitempty"`
+	// Kubernetes objects in Kind/name format
+	Objects []string `json:"objects,omitempty"`
+	// resulting file content, in dry run mode or for formats, which aren't
+	// written to a file
+	Content string `json:"content,omitempty"`
+	DryRun  bool   `json:"dryRun,omitempty"`
+}
+
+// runEmit writes the container definition in the format of --emit flag
+// instead of running it. In dry run mode the result is printed to stdout.
+func runEmit(
+	creator dbcreator.DBCreator,
+	runtime dbcreator.Runtime,
+	options dbcreator.CreateOptions,
+	info dbcreator.ConnectionInfo,
+	args CliArgs,
+) {
+	def, err := creator.GetServiceDefinition(options)
+	if err != nil {
+		exitWithError(ExitStatusFailedToEmit, err)
+	}
+	switch args.Emit {
+	case "k8s":
+		emitK8s(def, runtime, options, info, args)
+	default:
+		emitCompose(def, runtime, options, info, args)
+	}
+}
+
+func emitCompose(
+	def dbcreator.ServiceDefinition,
+	runtime dbcreator.Runtime,
+	options dbcreator.CreateOptions,
+	info dbcreator.ConnectionInfo,
+	args CliArgs,
+) {
+	path, err := filepath.Abs(args.ComposeFile)
+	if err != nil {
+		exitWithError(ExitStatusFailedToEmit, err)
+	}
+	project := compose.FromDefinition(def, compose.Options{
+		Runtime:     runtime,
+		BaseDir:     filepath.Dir(path),
+		StartPeriod: options.Timeout,
+	})
+	result := emitResult{Format: args.Emit, Path: path, DryRun: args.Dry.Enabled()}
+	for _, service := range project.Services {
+		result.Services = append(result.Services, service.Name)
+	}
+
+	if args.Dry.Enabled() {
+		existing, err := os.ReadFile(path)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			exitWithError(ExitStatusFailedToEmit, fmt.Errorf("error reading compose file: %w", err))
+		}
+		content, err := compose.Render(existing, project)
+		if err != nil {
+			exitWithError(ExitStatusFailedToEmit, err)
+		}
+		result.Content = string(content)
+	} else if err := compose.Merge(path, project); err != nil {
+		exitWithError(ExitStatusFailedToEmit, err)
+	}
+	mergeEnvFile(args, info, "compose file is written")
+
+	switch {
+	case isJSONOutput():
+		printJSON(result)
+	case args.Dry.Enabled():
+		fmt.Print(result.Content)
+	case args.Format != "":
+		printConnectionInfo(info, args.Format, options.GeneratedPassword)
+	default:
+		fmt.Printf("Written %s into %s\n", strings.Join(result.Services, ", "), args.ComposeFile)
+		printConnectionInfo(info, "", options.GeneratedPassword)
+	}
+}
+
+// emitK8s prints the manifests to stdout, so they can be piped into
+// `kubectl apply -f -` or redirected into a file
+func emitK8s(
+	def dbcreator.ServiceDefinition,
+	runtime dbcreator.Runtime,
+	options dbcreator.CreateOptions,
+	info dbcreator.ConnectionInfo,
+	args CliArgs,
+) {
+	objects, err := k8s.FromDefinition(def, k8s.Options{
+		Runtime:     runtime,
+		StartPeriod: options.Timeout,
+	})
+	if err != nil {
+		exitWithError(ExitStatusFailedToEmit, err)
+	}
+	content, err := k8s.Render(objects)
+	if err != nil {
+		exitWithError(ExitStatusFailedToEmit, err)
+	}
+	mergeEnvFile(args, info, "manifests are generated")
+
+	if isJSONOutput() {
+		result := emitResult{Format: args.Emit, Content: string(content), DryRun: args.Dry.Enabled()}
+		for _, obj := range objects {
+			result.Objects = append(result.Objects, obj.GetKind()+"/"+obj.GetName())
+		}
+		printJSON(result)
+		return
+	}
+	fmt.Print(string(content))
+}
@@ -0,0 +1,185 @@
+// Package engineapi is a minimal client of the Docker Engine HTTP API,
+// covering only the operations required to create and manage database
+// containers. Podman's docker-compatible API is supported as well.
+package engineapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"runtime"
+	"strings"
+)
+
+// apiVersion is the Engine API version requests are made with; 1.41 is
+// supported by Docker 20.10+ and Podman's compatibility API
+const apiVersion = "v1.41"
+
+// Client is an Engine API client
+type Client struct {
+	http    *http.Client
+	baseURL string
+	// Host is the daemon address, e.g. unix:///var/run/docker.sock
+	Host string
+}
+
+// Error is an error response of the Engine API
+type Error struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("engine API error (%d): %s", e.StatusCode, e.Message)
+}
+
+// IsNotFound reports whether the error is a "not found" API response, e.g. for
+// a missing container, volume or image
+func IsNotFound(err error) bool {
+	var apiErr *Error
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// ErrNamedPipeUnsupported is returned on Windows, where the daemon listens on
+// a named pipe by default
+var ErrNamedPipeUnsupported = errors.New("named pipes aren't supported by the API backend, set DOCKER_HOST (or CONTAINER_HOST for podman) to the daemon's TCP address, e.g. tcp://localhost:2375")
+
+// DefaultHost returns the Docker daemon address from DOCKER_HOST environment
+// variable or the platform default one
+func DefaultHost() (string, error) {
+	if host := os.Getenv("DOCKER_HOST"); host != "" {
+		return host, nil
+	}
+	if runtime.GOOS == "windows" {
+		return "", ErrNamedPipeUnsupported
+	}
+	return "unix:///var/run/docker.sock", nil
+}
+
+// DefaultPodmanHost returns the Podman service address from CONTAINER_HOST
+// environment variable, the rootless socket in XDG_RUNTIME_DIR, or the rootful
+// one if the variable isn't set
+func DefaultPodmanHost() (string, error) {
+	if host := os.Getenv("CONTAINER_HOST"); host != "" {
+		return host, nil
+	}
+	if runtime.GOOS == "windows" {
+		return "", ErrNamedPipeUnsupported
+	}
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return "unix://" + path.Join(dir, "podman", "podman.sock"), nil
+	}
+	return "unix:///run/podman/podman.sock", nil
+}
+
+// NewClient creates a client for the daemon at the host address. Only unix
+// sockets and plain TCP connections are supported.
+func NewClient(host string) (*Client, error) {
+	u, err := url.Parse(host)
+	if err != nil {
+		return nil, fmt.Errorf("invalid docker host '%s': %w", host, err)
+	}
+	switch u.Scheme {
+	case "unix":
+		socket := u.Path
+		transport := &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", socket)
+			},
+		}
+		return &Client{http: &http.Client{Transport: transport}, baseURL: "http://docker", Host: host}, nil
+	case "tcp", "http":
+		if os.Getenv("DOCKER_TLS_VERIFY") != "" {
+			return nil, errors.New("TLS connections to the docker host are not supported by the API backend")
+		}
+		return &Client{http: &http.Client{}, baseURL: "http://" + u.Host, Host: host}, nil
+	}
+	if u.Scheme == "npipe" {
+		return nil, ErrNamedPipeUnsupported
+	}
+	return nil, fmt.Errorf("unsupported docker host '%s', only unix:// and tcp:// addresses are supported by the API backend", host)
+}
+
+// Ping checks that the daemon is reachable
+func (c *Client) Ping(ctx context.Context) error {
+	resp, err := c.do(ctx, http.MethodGet, "/_ping", nil, nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// do sends the request with an optional JSON body, returning an Error for
+// non-successful responses. Caller must close the body of the response.
+func (c *Client) do(ctx context.Context, method string, path string, query url.Values, body any) (*http.Response, error) {
+	var reqBody io.Reader
+	if r, ok := body.(io.Reader); ok {
+		reqBody = r
+	} else if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reqBody = strings.NewReader(string(data))
+	}
+
+	u := c.baseURL + "/" + apiVersion + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	switch body.(type) {
+	case nil:
+	case io.Reader:
+		req.Header.Set("Content-Type", "application/x-tar")
+	default:
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to the docker host '%s': %w", c.Host, err)
+	}
+	if resp.StatusCode >= 400 {
+		defer resp.Body.Close()
+		return nil, readError(resp)
+	}
+	return resp, nil
+}
+
+// doJSON sends the request, decoding JSON response into the result, if it's
+// not nil
+func (c *Client) doJSON(ctx context.Context, method string, path string, query url.Values, body any, result any) error {
+	resp, err := c.do(ctx, method, path, query, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if result == nil {
+		_, err = io.Copy(io.Discard, resp.Body)
+		return err
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+func readError(resp *http.Response) error {
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	var payload struct {
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(data, &payload); err != nil || payload.Message == "" {
+		payload.Message = strings.TrimSpace(string(data))
+	}
+	return &Error{StatusCode: resp.StatusCode, Message: payload.Message}
+}
@@ -0,0 +1,110 @@
+package engineapi
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func frame(stream byte, payload string) []byte {
+	header := make([]byte, 8)
+	header[0] = stream
+	binary.BigEndian.PutUint32(header[4:], uint32(len(payload)))
+	return append(header, payload...)
+}
+
+func newTestClient(t *testing.T, handler http.Handler) *Client {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	client, err := NewClient(strings.Replace(srv.URL, "http://", "tcp://", 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func TestClientExec(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1.41/containers/abc/exec", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"Id":"exec1"}`))
+	})
+	mux.HandleFunc("POST /v1.41/exec/exec1/start", func(w http.ResponseWriter, r *http.Request) {
+		w.Write(frame(1, "Msg 911, Level 16\n"))
+		w.Write(frame(2, "Sqlcmd: Error\n"))
+	})
+	mux.HandleFunc("GET /v1.41/exec/exec1/json", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"ExitCode":1}`))
+	})
+	client := newTestClient(t, mux)
+
+	out, code, err := client.Exec(context.Background(), "abc", []string{"sqlcmd"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code != 1 {
+		t.Errorf("Unexpected exit code, want 1, got %d", code)
+	}
+	if want := "Msg 911, Level 16\nSqlcmd: Error\n"; out != want {
+		t.Errorf("Unexpected output, want %q, got %q", want, out)
+	}
+}
+
+func TestClientError(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message":"No such image: postgres:latest"}`))
+	}))
+
+	_, err := client.ContainerCreate(context.Background(), "foo", ContainerConfig{Image: "postgres:latest"})
+	if !IsNotFound(err) {
+		t.Fatalf("Expected not found error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "No such image") {
+		t.Errorf("Expected the daemon's message in the error, got %v", err)
+	}
+}
+
+func TestDefaultHost(t *testing.T) {
+	tests := []struct {
+		name string
+		host func() (string, error)
+		env  map[string]string
+		want string
+	}{
+		{"docker", DefaultHost, map[string]string{"DOCKER_HOST": ""}, "unix:///var/run/docker.sock"},
+		{"docker from env", DefaultHost, map[string]string{"DOCKER_HOST": "tcp://localhost:2375"}, "tcp://localhost:2375"},
+		{"podman from env", DefaultPodmanHost, map[string]string{"CONTAINER_HOST": "unix:///tmp/podman.sock", "DOCKER_HOST": "tcp://localhost:2375"}, "unix:///tmp/podman.sock"},
+		{"podman rootless", DefaultPodmanHost, map[string]string{"CONTAINER_HOST": "", "XDG_RUNTIME_DIR": "/run/user/1000"}, "unix:///run/user/1000/podman/podman.sock"},
+		{"podman rootful", DefaultPodmanHost, map[string]string{"CONTAINER_HOST": "", "XDG_RUNTIME_DIR": ""}, "unix:///run/podman/podman.sock"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if runtime.GOOS == "windows" {
+				t.Skip("named pipes are the default on Windows")
+			}
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			got, err := tt.host()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("Want %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestNewClientNamedPipe(t *testing.T) {
+	_, err := NewClient("npipe:////./pipe/docker_engine")
+	if !errors.Is(err, ErrNamedPipeUnsupported) {
+		t.Errorf("Want ErrNamedPipeUnsupported, got %v", err)
+	}
+}
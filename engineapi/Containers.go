@@ -0,0 +1,147 @@
+package engineapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ContainerConfig is a subset of the container create request body
+type ContainerConfig struct {
+	Hostname     string              `json:"Hostname,omitempty"`
+	User         string              `json:"User,omitempty"`
+	Env          []string            `json:"Env,omitempty"`
+	Cmd          []string            `json:"Cmd,omitempty"`
+	Entrypoint   []string            `json:"Entrypoint,omitempty"`
+	Image        string              `json:"Image"`
+	Labels       map[string]string   `json:"Labels,omitempty"`
+	ExposedPorts map[string]struct{} `json:"ExposedPorts,omitempty"`
+	HostConfig   HostConfig          `json:"HostConfig"`
+}
+
+// HostConfig is a subset of the container's host configuration
+type HostConfig struct {
+	Binds        []string                 `json:"Binds,omitempty"`
+	PortBindings map[string][]PortBinding `json:"PortBindings,omitempty"`
+	UsernsMode   string                   `json:"UsernsMode,omitempty"`
+}
+
+// PortBinding is a host address, a container port is published on
+type PortBinding struct {
+	HostIP   string `json:"HostIp"`
+	HostPort string `json:"HostPort"`
+}
+
+// ContainerState is a subset of the container's inspect state
+type ContainerState struct {
+	Status   string `json:"Status"`
+	Running  bool   `json:"Running"`
+	ExitCode int    `json:"ExitCode"`
+}
+
+// ContainerSummary is an item of the containers list
+type ContainerSummary struct {
+	ID     string            `json:"Id"`
+	Names  []string          `json:"Names"`
+	Image  string            `json:"Image"`
+	Status string            `json:"Status"`
+	Labels map[string]string `json:"Labels"`
+	Ports  []Port            `json:"Ports"`
+}
+
+// Port is a container's published port
+type Port struct {
+	IP          string `json:"IP"`
+	PrivatePort uint16 `json:"PrivatePort"`
+	PublicPort  uint16 `json:"PublicPort"`
+	Type        string `json:"Type"`
+}
+
+// ContainerCreate creates a new container, returning its ID. Returns a "not
+// found" error, if the image isn't pulled yet.
+func (c *Client) ContainerCreate(ctx context.Context, name string, config ContainerConfig) (string, error) {
+	query := url.Values{}
+	if name != "" {
+		query.Set("name", name)
+	}
+	var result struct {
+		ID string `json:"Id"`
+	}
+	err := c.doJSON(ctx, http.MethodPost, "/containers/create", query, config, &result)
+	return result.ID, err
+}
+
+// ContainerStart starts the created container
+func (c *Client) ContainerStart(ctx context.Context, id string) error {
+	return c.doJSON(ctx, http.MethodPost, "/containers/"+url.PathEscape(id)+"/start", nil, nil, nil)
+}
+
+// ContainerStop stops the running container
+func (c *Client) ContainerStop(ctx context.Context, id string) error {
+	return c.doJSON(ctx, http.MethodPost, "/containers/"+url.PathEscape(id)+"/stop", nil, nil, nil)
+}
+
+// ContainerRemove removes the container, optionally killing it if it's
+// running and removing its anonymous volumes
+func (c *Client) ContainerRemove(ctx context.Context, id string, force bool, volumes bool) error {
+	query := url.Values{}
+	query.Set("force", fmt.Sprint(force))
+	query.Set("v", fmt.Sprint(volumes))
+	return c.doJSON(ctx, http.MethodDelete, "/containers/"+url.PathEscape(id), query, nil, nil)
+}
+
+// ContainerInspect returns the container's state
+func (c *Client) ContainerInspect(ctx context.Context, id string) (ContainerState, error) {
+	var result struct {
+		State ContainerState `json:"State"`
+	}
+	err := c.doJSON(ctx, http.MethodGet, "/containers/"+url.PathEscape(id)+"/json", nil, nil, &result)
+	return result.State, err
+}
+
+// ContainerLogs returns the last tail lines of the container's stdout and
+// stderr combined, or all of them if tail isn't positive
+func (c *Client) ContainerLogs(ctx context.Context, id string, tail int) (string, error) {
+	query := url.Values{}
+	query.Set("stdout", "true")
+	query.Set("stderr", "true")
+	query.Set("tail", "all")
+	if tail > 0 {
+		query.Set("tail", fmt.Sprint(tail))
+	}
+	resp, err := c.do(ctx, http.MethodGet, "/containers/"+url.PathEscape(id)+"/logs", query, nil)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	var out strings.Builder
+	err = demux(resp.Body, &out)
+	return out.String(), err
+}
+
+// ContainerList returns all of the containers (including stopped ones) with
+// the label
+func (c *Client) ContainerList(ctx context.Context, label string) ([]ContainerSummary, error) {
+	filters, err := json.Marshal(map[string][]string{"label": {label}})
+	if err != nil {
+		return nil, err
+	}
+	query := url.Values{}
+	query.Set("all", "true")
+	query.Set("filters", string(filters))
+	var result []ContainerSummary
+	err = c.doJSON(ctx, http.MethodGet, "/containers/json", query, nil, &result)
+	return result, err
+}
+
+// CopyToContainer extracts the tar archive into the directory inside of the
+// container
+func (c *Client) CopyToContainer(ctx context.Context, id string, dir string, archive io.Reader) error {
+	query := url.Values{}
+	query.Set("path", dir)
+	return c.doJSON(ctx, http.MethodPut, "/containers/"+url.PathEscape(id)+"/archive", query, archive, nil)
+}
@@ -0,0 +1,43 @@
+package engineapi
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// Exec runs the command inside of the running container, returning its
+// stdout and stderr combined and the exit code
+func (c *Client) Exec(ctx context.Context, id string, cmd []string) (string, int, error) {
+	var created struct {
+		ID string `json:"Id"`
+	}
+	err := c.doJSON(ctx, http.MethodPost, "/containers/"+url.PathEscape(id)+"/exec", nil, map[string]any{
+		"AttachStdout": true,
+		"AttachStderr": true,
+		"Cmd":          cmd,
+	}, &created)
+	if err != nil {
+		return "", 0, err
+	}
+
+	resp, err := c.do(ctx, http.MethodPost, "/exec/"+url.PathEscape(created.ID)+"/start", nil, map[string]any{
+		"Detach": false,
+		"Tty":    false,
+	})
+	if err != nil {
+		return "", 0, err
+	}
+	defer resp.Body.Close()
+	var out bytes.Buffer
+	if err := demux(resp.Body, &out); err != nil {
+		return out.String(), 0, err
+	}
+
+	var inspect struct {
+		ExitCode int `json:"ExitCode"`
+	}
+	err = c.doJSON(ctx, http.MethodGet, "/exec/"+url.PathEscape(created.ID)+"/json", nil, nil, &inspect)
+	return out.String(), inspect.ExitCode, err
+}
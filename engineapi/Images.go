@@ -0,0 +1,41 @@
+package engineapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// ImagePull pulls the image, blocking until it's done
+func (c *Client) ImagePull(ctx context.Context, image string, tag string) error {
+	query := url.Values{}
+	query.Set("fromImage", image)
+	query.Set("tag", tag)
+	resp, err := c.do(ctx, http.MethodPost, "/images/create", query, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// progress is streamed as JSON messages, failures are reported in them as
+	// well, while the response status is already sent
+	dec := json.NewDecoder(resp.Body)
+	for {
+		var msg struct {
+			Error string `json:"error"`
+		}
+		err := dec.Decode(&msg)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if msg.Error != "" {
+			return errors.New(msg.Error)
+		}
+	}
+}
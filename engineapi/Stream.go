@@ -0,0 +1,28 @@
+package engineapi
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// demux copies the multiplexed stdout/stderr stream of a non-TTY container
+// into the writer. Each frame is prefixed with an 8 bytes header: stream type,
+// 3 zero bytes and big-endian uint32 size of the payload.
+func demux(r io.Reader, w io.Writer) error {
+	var header [8]byte
+	for {
+		_, err := io.ReadFull(r, header[:])
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("error reading the output stream: %w", err)
+		}
+		size := int64(binary.BigEndian.Uint32(header[4:]))
+		if _, err := io.CopyN(w, r, size); err != nil {
+			return fmt.Errorf("error reading the output stream: %w", err)
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package engineapi
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// VolumeExists reports whether the named volume exists
+func (c *Client) VolumeExists(ctx context.Context, name string) (bool, error) {
+	err := c.doJSON(ctx, http.MethodGet, "/volumes/"+url.PathEscape(name), nil, nil, nil)
+	if IsNotFound(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// VolumeRemove removes the named volume
+func (c *Client) VolumeRemove(ctx context.Context, name string) error {
+	return c.doJSON(ctx, http.MethodDelete, "/volumes/"+url.PathEscape(name), nil, nil, nil)
+}
@@ -0,0 +1,328 @@
+// Package k8s exports database service definitions as Kubernetes manifests,
+// e.g. for a local kind or minikube cluster
+package k8s
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/religiosa1/init-docker-db/dbcreator"
+)
+
+// Options of the definition conversion
+type Options struct {
+	// runtime, whose image naming is followed
+	Runtime dbcreator.Runtime
+	// time for the database to initialize, before the startup probe fails
+	StartPeriod time.Duration
+	// size of the persistent volume claims, created for named volumes
+	StorageSize string
+}
+
+const (
+	probePeriod           = 5 * time.Second
+	probeTimeout          = 10 * time.Second
+	probeFailureThreshold = 5
+	// init job fails, until the database is ready, so it's retried with
+	// exponential backoff, giving the database several minutes to start
+	initBackoffLimit   = 8
+	defaultStorageSize = "1Gi"
+	nameLabel          = "app.kubernetes.io/name"
+	managedByLabel     = "app.kubernetes.io/managed-by"
+)
+
+// DNS-1035 label, required for service names, which is also valid for other
+// resources
+var namePattern = regexp.MustCompile(`^[a-z]([-a-z0-9]{0,61}[a-z0-9])?$`)
+
+// FromDefinition converts the service definition into the list of manifests:
+// a secret with the environment variables and a config map with the mounted
+// files, if there are any, persistent volume claims, a deployment, a service
+// and an optional job for the init container.
+// Host paths aren't available on the cluster nodes, so bind-mounted files
+// (init scripts and config files) are read into the config map, and host
+// directories are replaced by persistent volume claims.
+func FromDefinition(def dbcreator.ServiceDefinition, opts Options) ([]Object, error) {
+	if opts.StartPeriod == 0 {
+		opts.StartPeriod = dbcreator.DefaultTimeout
+	}
+	if opts.StorageSize == "" {
+		opts.StorageSize = defaultStorageSize
+	}
+	name := def.Spec.Name
+	if err := validateName(name); err != nil {
+		return nil, err
+	}
+
+	secret := Secret{
+		TypeMeta:   TypeMeta{APIVersion: "v1", Kind: "Secret"},
+		ObjectMeta: objectMeta(name),
+		Type:       "Opaque",
+		StringData: map[string]string{},
+	}
+	files := files{ConfigMap: ConfigMap{
+		TypeMeta:   TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+		ObjectMeta: objectMeta(name),
+		Data:       map[string]string{},
+		BinaryData: map[string]string{},
+	}, keys: map[string]string{}}
+
+	db, volumes, claims, err := container(def.Spec, name, &secret, &files, opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(def.HealthCheck) > 0 {
+		db.ReadinessProbe = &Probe{
+			Exec:             ExecAction{Command: def.HealthCheck},
+			PeriodSeconds:    seconds(probePeriod),
+			TimeoutSeconds:   seconds(probeTimeout),
+			FailureThreshold: probeFailureThreshold,
+		}
+		db.StartupProbe = &Probe{
+			Exec:             ExecAction{Command: def.HealthCheck},
+			PeriodSeconds:    seconds(probePeriod),
+			TimeoutSeconds:   seconds(probeTimeout),
+			FailureThreshold: max(1, int((opts.StartPeriod+probePeriod-1)/probePeriod)),
+		}
+	}
+	db.Ports = []ContainerPort{{Name: "db", ContainerPort: def.Spec.Port}}
+
+	labels := map[string]string{nameLabel: name}
+	deployment := Deployment{
+		TypeMeta:   TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		ObjectMeta: objectMeta(name),
+		Spec: DeploymentSpec{
+			Replicas: 1,
+			// two instances can't share the same data volume
+			Strategy: DeploymentStrategy{Type: "Recreate"},
+			Selector: LabelSelector{MatchLabels: labels},
+			Template: PodTemplateSpec{
+				Metadata: ObjectMeta{Labels: labels},
+				Spec: PodSpec{
+					Hostname:   def.Spec.Hostname,
+					Containers: []Container{db},
+					Volumes:    volumes,
+				},
+			},
+		},
+	}
+	service := Service{
+		TypeMeta:   TypeMeta{APIVersion: "v1", Kind: "Service"},
+		ObjectMeta: objectMeta(name),
+		Spec: ServiceSpec{
+			Selector: labels,
+			Ports:    []ServicePort{{Name: "db", Port: def.Spec.Port, TargetPort: "db"}},
+		},
+	}
+	workloads := []Object{&deployment, &service}
+
+	if def.Init != nil {
+		initName := def.Init.Name
+		if err := validateName(initName); err != nil {
+			return nil, err
+		}
+		init, volumes, initClaims, err := container(*def.Init, initName, &secret, &files, opts)
+		if err != nil {
+			return nil, err
+		}
+		claims = append(claims, initClaims...)
+		job := Job{
+			TypeMeta:   TypeMeta{APIVersion: "batch/v1", Kind: "Job"},
+			ObjectMeta: objectMeta(initName),
+			Spec: JobSpec{
+				BackoffLimit: initBackoffLimit,
+				Template: PodTemplateSpec{
+					Metadata: ObjectMeta{Labels: map[string]string{nameLabel: initName}},
+					Spec: PodSpec{
+						RestartPolicy: "Never",
+						Containers:    []Container{init},
+						Volumes:       volumes,
+					},
+				},
+			},
+		}
+		workloads = append(workloads, &job)
+	}
+
+	var objects []Object
+	if len(secret.StringData) > 0 {
+		objects = append(objects, &secret)
+	}
+	if len(files.keys) > 0 {
+		objects = append(objects, &files.ConfigMap)
+	}
+	objects = append(objects, claims...)
+	return append(objects, workloads...), nil
+}
+
+// container converts the spec into a container, storing its environment
+// variables in the secret and its bind-mounted files in the config map, and
+// returning its volumes and claims for the named volumes and host directories
+func container(spec dbcreator.ContainerSpec, name string, secret *Secret, files *files, opts Options) (Container, []Volume, []Object, error) {
+	c := Container{
+		Name:  spec.Name,
+		Image: opts.Runtime.Image(spec.Image, spec.Tag),
+		Args:  escapeAll(spec.Cmd),
+	}
+	if spec.Entrypoint != "" {
+		c.Command = []string{escape(spec.Entrypoint)}
+	}
+	for _, env := range spec.Env {
+		key, value, _ := strings.Cut(env, "=")
+		secret.StringData[key] = value
+		c.Env = append(c.Env, EnvVar{
+			Name:      key,
+			ValueFrom: EnvVarSource{SecretKeyRef: SecretKeySelector{Name: secret.Name, Key: key}},
+		})
+	}
+
+	var volumes []Volume
+	var claims []Object
+	filesVolume := Volume{Name: "files", ConfigMap: &ConfigMapVolumeSource{Name: files.Name}}
+	for i, mount := range spec.Mounts {
+		if mount.IsBind() {
+			key, err := files.add(mount.Source, path.Base(mount.Target))
+			if err != nil {
+				return c, nil, nil, err
+			}
+			if key != "" {
+				if !slices.ContainsFunc(volumes, func(v Volume) bool { return v.ConfigMap != nil }) {
+					volumes = append(volumes, filesVolume)
+				}
+				// mounting a single file, so the rest of the directory's content
+				// is preserved
+				c.VolumeMounts = append(c.VolumeMounts, VolumeMount{
+					Name:      filesVolume.Name,
+					MountPath: mount.Target,
+					SubPath:   key,
+					ReadOnly:  true,
+				})
+				continue
+			}
+		}
+
+		claimName := mount.Source
+		if mount.IsBind() {
+			// host directory is replaced by an empty claim, named after the container
+			claimName = fmt.Sprintf("%s-volume-%d", name, i)
+		}
+		if err := validateName(claimName); err != nil {
+			return c, nil, nil, err
+		}
+		volume := Volume{
+			Name:                  fmt.Sprintf("volume-%d", i),
+			PersistentVolumeClaim: &PersistentVolumeClaimVolumeSource{ClaimName: claimName},
+		}
+		claims = append(claims, &PersistentVolumeClaim{
+			TypeMeta:   TypeMeta{APIVersion: "v1", Kind: "PersistentVolumeClaim"},
+			ObjectMeta: objectMeta(claimName),
+			Spec: PersistentVolumeClaimSpec{
+				AccessModes: []string{"ReadWriteOnce"},
+				Resources: VolumeResourceRequirements{
+					Requests: map[string]string{"storage": opts.StorageSize},
+				},
+			},
+		})
+		volumes = append(volumes, volume)
+		c.VolumeMounts = append(c.VolumeMounts, VolumeMount{
+			Name:      volume.Name,
+			MountPath: mount.Target,
+			ReadOnly:  mount.ReadOnly,
+		})
+	}
+	return c, volumes, claims, nil
+}
+
+// files is a config map with the content of the bind-mounted files
+type files struct {
+	ConfigMap
+	// keys of the added files by their paths
+	keys map[string]string
+	// total size of the content
+	size int
+}
+
+// ConfigMap's maximum size, enforced by the API server
+const maxConfigMapSize = 1 << 20
+
+// invalid characters of the config map keys
+var keyInvalidChars = regexp.MustCompile(`[^-._a-zA-Z0-9]`)
+
+// add reads the file into the config map, returning its key, or an empty
+// string, if the path isn't a regular file (e.g. a host directory)
+func (f *files) add(source string, name string) (string, error) {
+	if key, ok := f.keys[source]; ok {
+		return key, nil
+	}
+	if info, err := os.Stat(source); err != nil || !info.Mode().IsRegular() {
+		return "", nil
+	}
+	content, err := os.ReadFile(source)
+	if err != nil {
+		return "", fmt.Errorf("error reading mounted file '%s': %w", source, err)
+	}
+	f.size += len(content)
+	if f.size > maxConfigMapSize {
+		return "", fmt.Errorf("mounted file '%s' doesn't fit into the ConfigMap, as the mounted files can't exceed 1MiB in total", source)
+	}
+
+	key := keyInvalidChars.ReplaceAllString(name, "_")
+	for i := 1; f.hasKey(key); i++ {
+		key = fmt.Sprintf("%d-%s", i, keyInvalidChars.ReplaceAllString(name, "_"))
+	}
+	if utf8.Valid(content) {
+		f.Data[key] = string(content)
+	} else {
+		f.BinaryData[key] = base64.StdEncoding.EncodeToString(content)
+	}
+	f.keys[source] = key
+	return key, nil
+}
+
+func (f *files) hasKey(key string) bool {
+	_, data := f.Data[key]
+	_, binary := f.BinaryData[key]
+	return data || binary
+}
+
+func validateName(name string) error {
+	if !namePattern.MatchString(name) {
+		return fmt.Errorf("'%s' isn't a valid Kubernetes resource name: it must consist of lower case alphanumeric characters or '-', start with a letter and be at most 63 characters long", name)
+	}
+	return nil
+}
+
+func objectMeta(name string) ObjectMeta {
+	return ObjectMeta{
+		Name:   name,
+		Labels: map[string]string{nameLabel: name, managedByLabel: "init-docker-db"},
+	}
+}
+
+func seconds(d time.Duration) int {
+	return int(d / time.Second)
+}
+
+// escape prevents Kubernetes from expanding $(VAR) references in the
+// container's command and arguments
+func escape(value string) string {
+	return strings.ReplaceAll(value, "$", "$$")
+}
+
+func escapeAll(values []string) []string {
+	if values == nil {
+		return nil
+	}
+	escaped := make([]string, len(values))
+	for i, v := range values {
+		escaped[i] = escape(v)
+	}
+	return escaped
+}
@@ -0,0 +1,209 @@
+package k8s
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/religiosa1/init-docker-db/dbcreator"
+	"gopkg.in/yaml.v3"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// apiTypes maps the kinds to their apiVersions and the Kubernetes API types,
+// the manifests are validated against
+var apiTypes = map[string]struct {
+	apiVersion string
+	new        func() runtime.Object
+}{
+	"Secret":                {"v1", func() runtime.Object { return &corev1.Secret{} }},
+	"ConfigMap":             {"v1", func() runtime.Object { return &corev1.ConfigMap{} }},
+	"PersistentVolumeClaim": {"v1", func() runtime.Object { return &corev1.PersistentVolumeClaim{} }},
+	"Deployment":            {"apps/v1", func() runtime.Object { return &appsv1.Deployment{} }},
+	"Service":               {"v1", func() runtime.Object { return &corev1.Service{} }},
+	"Job":                   {"batch/v1", func() runtime.Object { return &batchv1.Job{} }},
+}
+
+func TestFromDefinition(t *testing.T) {
+	dir := t.TempDir()
+	script := filepath.Join(dir, "init.sql")
+	compressed := filepath.Join(dir, "seed.sql.gz")
+	if err := os.WriteFile(script, []byte("CREATE TABLE t (id int);"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(compressed, []byte{0x1f, 0x8b, 0x08, 0xff}, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	def := dbcreator.ServiceDefinition{
+		Spec: dbcreator.ContainerSpec{
+			Name:     "db",
+			Hostname: "db",
+			Image:    "mcr.microsoft.com/mssql/server",
+			Tag:      "2022-latest",
+			Env:      []string{"ACCEPT_EULA=Y", "MSSQL_SA_PASSWORD=Pa$$word12"},
+			Port:     1433,
+			Mounts: []dbcreator.Mount{
+				{Source: "mssql-data", Target: "/var/opt/mssql"},
+				{Source: filepath.Join(dir, "backups"), Target: "/var/backups"},
+			},
+		},
+		HealthCheck: []string{"sqlcmd", "-Q", "SELECT 1"},
+		Init: &dbcreator.ContainerSpec{
+			Name:  "db-init",
+			Image: "mcr.microsoft.com/mssql/server",
+			Tag:   "2022-latest",
+			Env:   []string{"MSSQL_SA_PASSWORD=Pa$$word12"},
+			Mounts: []dbcreator.Mount{
+				{Source: script, Target: "/docker-entrypoint-initdb.d/init.sql", ReadOnly: true},
+				{Source: compressed, Target: "/docker-entrypoint-initdb.d/seed.sql.gz", ReadOnly: true},
+			},
+			Entrypoint: "bash",
+			Cmd:        []string{"-c", `sqlcmd -P "$MSSQL_SA_PASSWORD"`},
+		},
+	}
+	objects, err := FromDefinition(def, Options{Runtime: dbcreator.Docker})
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := Render(objects)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	docs := decodeAll(t, content)
+	var kinds []string
+	for _, doc := range docs {
+		kinds = append(kinds, doc.GetObjectKind().GroupVersionKind().Kind)
+		if name := doc.(metav1.Object).GetName(); !namePattern.MatchString(name) {
+			t.Errorf("Invalid metadata.name %q", name)
+		}
+	}
+	if got, want := strings.Join(kinds, ","), "Secret,ConfigMap,PersistentVolumeClaim,PersistentVolumeClaim,Deployment,Service,Job"; got != want {
+		t.Fatalf("Unexpected kinds, want %s, got %s", want, got)
+	}
+
+	secret := docs[0].(*corev1.Secret)
+	configMap := docs[1].(*corev1.ConfigMap)
+	deployment := docs[4].(*appsv1.Deployment)
+	service := docs[5].(*corev1.Service)
+	job := docs[6].(*batchv1.Job)
+	if got := secret.StringData["MSSQL_SA_PASSWORD"]; got != "Pa$$word12" {
+		t.Errorf("Expected the password in the secret, got %q", got)
+	}
+	labels := deployment.Spec.Template.Labels
+	if !maps.Equal(deployment.Spec.Selector.MatchLabels, labels) || !maps.Equal(service.Spec.Selector, labels) {
+		t.Error("Expected selectors to match the pod labels")
+	}
+	if maps.Equal(service.Spec.Selector, job.Spec.Template.Labels) {
+		t.Error("Expected the service not to select init job pods")
+	}
+	var claims []string
+	for _, v := range deployment.Spec.Template.Spec.Volumes {
+		if v.PersistentVolumeClaim != nil {
+			claims = append(claims, v.PersistentVolumeClaim.ClaimName)
+		}
+	}
+	if got, want := strings.Join(claims, ","), "mssql-data,db-volume-1"; got != want {
+		t.Errorf("Expected the named volume and the host directory to be claims %s, got %s", want, got)
+	}
+
+	if got := configMap.Data["init.sql"]; got != "CREATE TABLE t (id int);" {
+		t.Errorf("Expected the script in the config map, got %q", got)
+	}
+	if got := configMap.BinaryData["seed.sql.gz"]; !bytes.Equal(got, []byte{0x1f, 0x8b, 0x08, 0xff}) {
+		t.Errorf("Expected the compressed script in the config map binary data, got %v", got)
+	}
+	init := job.Spec.Template.Spec.Containers[0]
+	if got := job.Spec.Template.Spec.Volumes; len(got) != 1 || got[0].ConfigMap == nil || got[0].ConfigMap.Name != configMap.Name {
+		t.Errorf("Expected the job to mount the config map, got %v", got)
+	}
+	for _, mount := range init.VolumeMounts {
+		if mount.SubPath != path.Base(mount.MountPath) || !mount.ReadOnly {
+			t.Errorf("Expected the script to be mounted read-only from the config map, got %v", mount)
+		}
+	}
+	if len(init.Args) != 2 || init.Args[1] != `sqlcmd -P "$$MSSQL_SA_PASSWORD"` {
+		t.Errorf("Expected variables in args to be escaped, got %v", init.Args)
+	}
+	for _, env := range init.Env {
+		if _, ok := secret.StringData[env.ValueFrom.SecretKeyRef.Key]; !ok {
+			t.Errorf("Secret key %s is referenced, but missing", env.ValueFrom.SecretKeyRef.Key)
+		}
+	}
+}
+
+func TestFromDefinitionConfigMapLimit(t *testing.T) {
+	script := filepath.Join(t.TempDir(), "init.sql")
+	if err := os.WriteFile(script, make([]byte, maxConfigMapSize+1), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	spec := dbcreator.ContainerSpec{Name: "db", Mounts: []dbcreator.Mount{{Source: script, Target: "/init.sql"}}}
+	if _, err := FromDefinition(dbcreator.ServiceDefinition{Spec: spec}, Options{}); err == nil {
+		t.Error("expected FromDefinition to throw, but it didn't")
+	}
+}
+
+func TestFromDefinitionInvalidNames(t *testing.T) {
+	tests := []struct {
+		name string
+		spec dbcreator.ContainerSpec
+	}{
+		{"container name", dbcreator.ContainerSpec{Name: "My_DB"}},
+		{"volume name", dbcreator.ContainerSpec{Name: "db", Mounts: []dbcreator.Mount{{Source: "pg.data", Target: "/data"}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := FromDefinition(dbcreator.ServiceDefinition{Spec: tt.spec}, Options{}); err == nil {
+				t.Error("expected FromDefinition to throw, but it didn't")
+			}
+		})
+	}
+}
+
+// decodeAll strictly decodes the documents into the Kubernetes API types, so
+// unknown fields and fields of a wrong type are rejected
+func decodeAll(t *testing.T, content []byte) []runtime.Object {
+	t.Helper()
+	var docs []runtime.Object
+	dec := yaml.NewDecoder(bytes.NewReader(content))
+	for {
+		var doc map[string]any
+		err := dec.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			return docs
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		kind, _ := doc["kind"].(string)
+		apiType, ok := apiTypes[kind]
+		if !ok {
+			t.Fatalf("Unexpected kind %q", kind)
+		}
+		if doc["apiVersion"] != apiType.apiVersion {
+			t.Errorf("%s: expected apiVersion %s, got %v", kind, apiType.apiVersion, doc["apiVersion"])
+		}
+		data, err := json.Marshal(doc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		obj := apiType.new()
+		jsonDec := json.NewDecoder(bytes.NewReader(data))
+		jsonDec.DisallowUnknownFields()
+		if err := jsonDec.Decode(obj); err != nil {
+			t.Fatalf("%s doesn't match the API schema: %v\n%s", kind, err, data)
+		}
+		docs = append(docs, obj)
+	}
+}
@@ -0,0 +1,25 @@
+package k8s
+
+import (
+	"bytes"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Render encodes the objects as a multi-document YAML, which can be applied
+// with `kubectl apply -f`
+func Render(objects []Object) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	for _, obj := range objects {
+		if err := enc.Encode(obj); err != nil {
+			return nil, fmt.Errorf("error encoding %s '%s': %w", obj.GetKind(), obj.GetName(), err)
+		}
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,182 @@
+package k8s
+
+// Subset of the Kubernetes API objects, required for the database manifests.
+// Field names follow the API, so the rendered YAML can be applied as is.
+
+// Object is a Kubernetes API object
+type Object interface {
+	GetKind() string
+	GetName() string
+}
+
+type TypeMeta struct {
+	APIVersion string `yaml:"apiVersion"`
+	Kind       string `yaml:"kind"`
+}
+
+func (t TypeMeta) GetKind() string {
+	return t.Kind
+}
+
+type ObjectMeta struct {
+	Name   string            `yaml:"name,omitempty"`
+	Labels map[string]string `yaml:"labels,omitempty"`
+}
+
+func (m ObjectMeta) GetName() string {
+	return m.Name
+}
+
+type Secret struct {
+	TypeMeta   `yaml:",inline"`
+	ObjectMeta `yaml:"metadata"`
+	Type       string            `yaml:"type"`
+	StringData map[string]string `yaml:"stringData"`
+}
+
+type ConfigMap struct {
+	TypeMeta   `yaml:",inline"`
+	ObjectMeta `yaml:"metadata"`
+	Data       map[string]string `yaml:"data,omitempty"`
+	// base64-encoded values, which aren't valid UTF-8
+	BinaryData map[string]string `yaml:"binaryData,omitempty"`
+}
+
+type PersistentVolumeClaim struct {
+	TypeMeta   `yaml:",inline"`
+	ObjectMeta `yaml:"metadata"`
+	Spec       PersistentVolumeClaimSpec `yaml:"spec"`
+}
+
+type PersistentVolumeClaimSpec struct {
+	AccessModes []string                   `yaml:"accessModes"`
+	Resources   VolumeResourceRequirements `yaml:"resources"`
+}
+
+type VolumeResourceRequirements struct {
+	Requests map[string]string `yaml:"requests"`
+}
+
+type Deployment struct {
+	TypeMeta   `yaml:",inline"`
+	ObjectMeta `yaml:"metadata"`
+	Spec       DeploymentSpec `yaml:"spec"`
+}
+
+type DeploymentSpec struct {
+	Replicas int                `yaml:"replicas"`
+	Strategy DeploymentStrategy `yaml:"strategy"`
+	Selector LabelSelector      `yaml:"selector"`
+	Template PodTemplateSpec    `yaml:"template"`
+}
+
+type DeploymentStrategy struct {
+	Type string `yaml:"type"`
+}
+
+type LabelSelector struct {
+	MatchLabels map[string]string `yaml:"matchLabels"`
+}
+
+type Job struct {
+	TypeMeta   `yaml:",inline"`
+	ObjectMeta `yaml:"metadata"`
+	Spec       JobSpec `yaml:"spec"`
+}
+
+type JobSpec struct {
+	BackoffLimit int             `yaml:"backoffLimit"`
+	Template     PodTemplateSpec `yaml:"template"`
+}
+
+type PodTemplateSpec struct {
+	Metadata ObjectMeta `yaml:"metadata"`
+	Spec     PodSpec    `yaml:"spec"`
+}
+
+type PodSpec struct {
+	Hostname      string      `yaml:"hostname,omitempty"`
+	RestartPolicy string      `yaml:"restartPolicy,omitempty"`
+	Containers    []Container `yaml:"containers"`
+	Volumes       []Volume    `yaml:"volumes,omitempty"`
+}
+
+type Container struct {
+	Name           string          `yaml:"name"`
+	Image          string          `yaml:"image"`
+	Command        []string        `yaml:"command,omitempty"`
+	Args           []string        `yaml:"args,omitempty"`
+	Env            []EnvVar        `yaml:"env,omitempty"`
+	Ports          []ContainerPort `yaml:"ports,omitempty"`
+	VolumeMounts   []VolumeMount   `yaml:"volumeMounts,omitempty"`
+	ReadinessProbe *Probe          `yaml:"readinessProbe,omitempty"`
+	StartupProbe   *Probe          `yaml:"startupProbe,omitempty"`
+}
+
+type EnvVar struct {
+	Name      string       `yaml:"name"`
+	ValueFrom EnvVarSource `yaml:"valueFrom"`
+}
+
+type EnvVarSource struct {
+	SecretKeyRef SecretKeySelector `yaml:"secretKeyRef"`
+}
+
+type SecretKeySelector struct {
+	Name string `yaml:"name"`
+	Key  string `yaml:"key"`
+}
+
+type ContainerPort struct {
+	Name          string `yaml:"name"`
+	ContainerPort uint16 `yaml:"containerPort"`
+}
+
+type VolumeMount struct {
+	Name      string `yaml:"name"`
+	MountPath string `yaml:"mountPath"`
+	SubPath   string `yaml:"subPath,omitempty"`
+	ReadOnly  bool   `yaml:"readOnly,omitempty"`
+}
+
+type Volume struct {
+	Name                  string                             `yaml:"name"`
+	ConfigMap             *ConfigMapVolumeSource             `yaml:"configMap,omitempty"`
+	PersistentVolumeClaim *PersistentVolumeClaimVolumeSource `yaml:"persistentVolumeClaim,omitempty"`
+}
+
+type ConfigMapVolumeSource struct {
+	Name string `yaml:"name"`
+}
+
+type PersistentVolumeClaimVolumeSource struct {
+	ClaimName string `yaml:"claimName"`
+}
+
+type Probe struct {
+	Exec             ExecAction `yaml:"exec"`
+	PeriodSeconds    int        `yaml:"periodSeconds"`
+	TimeoutSeconds   int        `yaml:"timeoutSeconds"`
+	FailureThreshold int        `yaml:"failureThreshold"`
+}
+
+type ExecAction struct {
+	Command []string `yaml:"command"`
+}
+
+type Service struct {
+	TypeMeta   `yaml:",inline"`
+	ObjectMeta `yaml:"metadata"`
+	Spec       ServiceSpec `yaml:"spec"`
+}
+
+type ServiceSpec struct {
+	Selector map[string]string `yaml:"selector"`
+	Ports    []ServicePort     `yaml:"ports"`
+}
+
+type ServicePort struct {
+	Name       string `yaml:"name"`
+	Port       uint16 `yaml:"port"`
+	TargetPort string `yaml:"targetPort"`
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/religiosa1/init-docker-db/managed"
+)
+
+type ListArgs struct {
+	Verbose bool `short:"v" help:"run with verbose logging"`
+}
+
+func runList(ctx context.Context, args ListArgs) {
+	out := io.Writer(os.Stdout)
+	if isJSONOutput() {
+		out = os.Stderr
+	}
+	backend := getBackend(ctx, "", args.Verbose, out, false)
+	containers, err := managed.List(ctx, backend)
+	if err != nil {
+		exitWithError(ExitStatusFailedToListContainers, err)
+	}
+
+	if isJSONOutput() {
+		printJSON(listResult{Containers: makeContainerResults(containers)})
+		return
+	}
+
+	if len(containers) == 0 {
+		fmt.Println("No containers created by init-docker-db found")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "NAME\tENGINE\tTAG\tPORTS\tSTATUS\tAGE")
+	now := time.Now()
+	for _, c := range containers {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			c.Name, c.Engine, c.Tag(), c.Ports, c.Status, formatAge(c.CreatedAt, now))
+	}
+	if err := w.Flush(); err != nil {
+		exitWithError(ExitStatusFailedToListContainers, err)
+	}
+}
+
+// formatAge formats the container's age in a short human-readable form
+func formatAge(createdAt time.Time, now time.Time) string {
+	if createdAt.IsZero() {
+		return "unknown"
+	}
+	age := now.Sub(createdAt)
+	switch {
+	case age < time.Minute:
+		return fmt.Sprintf("%ds", int(age.Seconds()))
+	case age < time.Hour:
+		return fmt.Sprintf("%dm", int(age.Minutes()))
+	case age < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(age.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(age.Hours()/24))
+	}
+}
@@ -1,45 +1,87 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net"
 	"os"
-	"os/exec"
+	"os/signal"
 	"regexp"
 	"runtime/debug"
+	"strconv"
+	"strings"
+	"syscall"
 	"text/tabwriter"
+	"time"
 
 	"github.com/alecthomas/kong"
 	"github.com/charmbracelet/huh"
+	"github.com/religiosa1/init-docker-db/config"
 	"github.com/religiosa1/init-docker-db/creators/mongo"
 	"github.com/religiosa1/init-docker-db/creators/mssql"
 	"github.com/religiosa1/init-docker-db/creators/mysql"
 	"github.com/religiosa1/init-docker-db/creators/postgres"
 	"github.com/religiosa1/init-docker-db/creators/redis"
 	"github.com/religiosa1/init-docker-db/dbcreator"
+	"github.com/religiosa1/init-docker-db/dotenv"
 	"github.com/religiosa1/init-docker-db/randomname"
 )
 
 var ldVersion = "" // Version set by -ldflags during the Taskfile build
 
 type CliArgs struct {
-	ContainerName  string   `arg:"" optional:"" name:"containerName" help:"name of the database container to be created"`
-	Type           string   `short:"t" help:"database type"`
-	User           string   `short:"u" help:"database user"`
-	Database       string   `short:"d" help:"database name"`
-	Password       string   `short:"P" help:"user's password"`
-	Port           []string `short:"p" sep:"none" help:"port with optional IP address to which database will be mapped to"`
-	Public         bool     `help:"expose default port to outside world by mapping to 0.0.0.0 IP address"`
-	Tag            string   `short:"T" help:"docker tag to use with the container"`
-	NonInteractive bool     `short:"n" help:"exit if any required parameters are missing"`
-	Dry            bool     `short:"D" help:"dry run, printing docker command to stdout, without actually running it"`
-	Verbose        bool     `short:"v" help:"run with verbose logging"`
-	Version        bool     `help:"show version and exit"`
-	Help           bool     `short:"h" help:"show help message and exit"`
+	ContainerName      string        `arg:"" optional:"" name:"containerName" help:"name of the database container to be created" env:"INIT_DOCKER_DB_CONTAINER_NAME"`
+	Profile            string        `placeholder:"NAME" help:"name of the profile from the config file to use" env:"INIT_DOCKER_DB_PROFILE"`
+	Type               string        `short:"t" help:"database type" env:"INIT_DOCKER_DB_TYPE"`
+	User               string        `short:"u" help:"database user" env:"INIT_DOCKER_DB_USER"`
+	Database           string        `short:"d" help:"database name" env:"INIT_DOCKER_DB_DATABASE"`
+	Password           string        `short:"P" help:"user's password" env:"INIT_DOCKER_DB_PASSWORD"`
+	GeneratePassword   bool          `help:"generate a random password, meeting the database's password policy" env:"INIT_DOCKER_DB_GENERATE_PASSWORD"`
+	RootPassword       string        `placeholder:"PASSWORD" help:"separate password of the root account for MySQL and Mongo (user's password by default)" env:"INIT_DOCKER_DB_ROOT_PASSWORD"`
+	RandomRootPassword bool          `help:"generate a random MySQL root password instead, printed to the container logs as GENERATED ROOT PASSWORD" env:"INIT_DOCKER_DB_RANDOM_ROOT_PASSWORD"`
+	PasswordPolicy     string        `placeholder:"LEVEL" help:"MySQL validate_password policy level to check the password against: low, medium or strong" env:"INIT_DOCKER_DB_PASSWORD_POLICY"`
+	UserHost           string        `placeholder:"PATTERN" help:"MySQL host pattern of the user, e.g. localhost (% by default)" env:"INIT_DOCKER_DB_USER_HOST"`
+	AuthPlugin         string        `enum:",caching_sha2_password,mysql_native_password" default:"" placeholder:"PLUGIN" help:"MySQL authentication plugin of the users: caching_sha2_password or mysql_native_password for legacy drivers" env:"INIT_DOCKER_DB_AUTH_PLUGIN"`
+	ACL                string        `placeholder:"RULES" help:"Redis ACL rules of the created user (\"~* &* +@all\" by default)" env:"INIT_DOCKER_DB_ACL"`
+	Persistence        string        `enum:",none,rdb,aof" default:"" placeholder:"MODE" help:"Redis persistence mode: none, rdb snapshots or aof (rdb by default)" env:"INIT_DOCKER_DB_PERSISTENCE"`
+	Save               string        `placeholder:"INTERVALS" help:"Redis rdb snapshot intervals as \"SECONDS CHANGES ...\" pairs (\"60 1\" by default)" env:"INIT_DOCKER_DB_SAVE"`
+	Appendfsync        string        `enum:",always,everysec,no" default:"" placeholder:"POLICY" help:"Redis aof fsync policy: always, everysec or no" env:"INIT_DOCKER_DB_APPENDFSYNC"`
+	Maxmemory          string        `placeholder:"SIZE" help:"Redis memory limit, e.g. 100mb" env:"INIT_DOCKER_DB_MAXMEMORY"`
+	MaxmemoryPolicy    string        `enum:",noeviction,allkeys-lru,allkeys-lfu,allkeys-random,volatile-lru,volatile-lfu,volatile-random,volatile-ttl" default:"" placeholder:"POLICY" help:"Redis eviction policy, applied when the memory limit is reached, e.g. allkeys-lru" env:"INIT_DOCKER_DB_MAXMEMORY_POLICY"`
+	RedisConf          string        `type:"existingfile" placeholder:"PATH" help:"redis.conf file to run Redis with; other Redis flags override its settings" env:"INIT_DOCKER_DB_REDIS_CONF"`
+	ReplicaSet         ReplicaSet    `placeholder:"NAME" help:"run Mongo as a single-node replica set, named with --replica-set=NAME (rs0 if the value is omitted), enabling transactions and change streams" env:"INIT_DOCKER_DB_REPLICA_SET"`
+	Port               []string      `short:"p" sep:"none" help:"port with optional IP address to which database will be mapped to" env:"INIT_DOCKER_DB_PORT"`
+	Public             bool          `help:"expose default port to outside world by mapping to 0.0.0.0 IP address" env:"INIT_DOCKER_DB_PUBLIC"`
+	Tag                string        `short:"T" help:"docker tag to use with the container" env:"INIT_DOCKER_DB_TAG"`
+	Volume             string        `placeholder:"NAME|PATH" help:"named volume or host directory to persist the database data" env:"INIT_DOCKER_DB_VOLUME"`
+	Init               []string      `type:"path" sep:"none" placeholder:"FILE|DIR" help:"seed script file or directory to run after the database is created (.sql, .sql.gz, .js or .sh depending on the database type)" env:"INIT_DOCKER_DB_INIT"`
+	NonInteractive     bool          `short:"n" help:"exit if any required parameters are missing" env:"INIT_DOCKER_DB_NON_INTERACTIVE"`
+	Dry                DryRun        `short:"D" help:"dry run, printing docker commands to stdout as a bash script (or powershell with --dry=powershell), without actually running them" env:"INIT_DOCKER_DB_DRY"`
+	Verbose            bool          `short:"v" help:"run with verbose logging" env:"INIT_DOCKER_DB_VERBOSE"`
+	Format             string        `short:"f" help:"print only the connection string in the specified format (uri, dsn, ado, jdbc) after creation" env:"INIT_DOCKER_DB_FORMAT"`
+	EnvFile            string        `type:"path" placeholder:"PATH" help:"write or merge the database credentials into the specified dotenv file" env:"INIT_DOCKER_DB_ENV_FILE"`
+	EnvPrefix          string        `placeholder:"PREFIX" help:"prefix for the variable names written to the dotenv file" env:"INIT_DOCKER_DB_ENV_PREFIX"`
+	Wait               bool          `negatable:"" default:"true" help:"wait for the database to be ready to accept connections" env:"INIT_DOCKER_DB_WAIT"`
+	Timeout            time.Duration `default:"60s" help:"maximum time to wait for the database to be ready" env:"INIT_DOCKER_DB_TIMEOUT"`
+	KeepOnFailure      bool          `help:"keep the container, if its creation fails or is interrupted, for inspection" env:"INIT_DOCKER_DB_KEEP_ON_FAILURE"`
+	Emit               string        `enum:",compose,k8s" default:"" placeholder:"FORMAT" help:"instead of running the container, write its definition in the specified format (compose, or k8s manifests printed to stdout)" env:"INIT_DOCKER_DB_EMIT"`
+	ComposeFile        string        `type:"path" default:"compose.yaml" placeholder:"PATH" help:"compose file to write or merge the service into with --emit compose" env:"INIT_DOCKER_DB_COMPOSE_FILE"`
 }
 
-var CLI CliArgs
+type Commands struct {
+	Create  CliArgs     `cmd:"" default:"withargs" help:"create a new database container (default command, which can be omitted)"`
+	List    ListArgs    `cmd:"" help:"list database containers created by init-docker-db"`
+	Destroy DestroyArgs `cmd:"" help:"stop and remove database containers created by init-docker-db"`
+	Compose CliArgs     `cmd:"" help:"write the database service into a compose file instead of running it (same as create --emit compose)"`
+	Output  string      `short:"o" enum:"text,json" default:"text" env:"INIT_DOCKER_DB_OUTPUT" help:"output format: text or json"`
+	Runtime string      `enum:"docker,podman,nerdctl," default:"" env:"INIT_DOCKER_DB_RUNTIME" help:"container runtime: docker, podman or nerdctl (detected from PATH by default)"`
+	Backend string      `enum:"auto,cli,api" default:"auto" env:"INIT_DOCKER_DB_BACKEND" help:"how to manage containers: through the runtime's cli, its Engine api, or auto (cli if it's installed)"`
+	Version bool        `help:"show version and exit"`
+}
+
+var CLI Commands
 
 type ExitStatus int
 
@@ -48,56 +90,212 @@ const (
 	ExitStatusDockerNotFound
 	ExitStatusFailedToGetCreator
 	ExitStatusFailedToCreateContainer
+	ExitStatusFailedToListContainers
+	ExitStatusFailedToDestroyContainers
+	ExitStatusFailedToWriteEnvFile
+	ExitStatusFailedToLoadConfig
+	ExitStatusFailedToEmit
+	// conventional 128 + SIGINT exit status
+	ExitStatusInterrupted ExitStatus = 130
 )
 
 func main() {
-	kong.Parse(
+	configFiles, err := config.Discover(".")
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(int(ExitStatusFailedToLoadConfig))
+	}
+
+	configResolver := config.NewResolver(configFiles, "create", "compose")
+	ctx := kong.Parse(
 		&CLI,
 		kong.Description("Create a disposable database docker container."),
 		kong.Help(helpPrinter),
+		kong.Resolvers(configResolver),
 	)
+	createArgs := CLI.Create
+	if ctx.Selected().Name == "compose" {
+		createArgs = CLI.Compose
+		createArgs.Emit = "compose"
+	}
+	if createArgs.Profile != "" && !configResolver.HasProfile(createArgs.Profile) {
+		ctx.Fatalf("profile '%s' is not found in the config files", createArgs.Profile)
+	}
 
 	if CLI.Version {
 		showVersion()
 		return
 	}
 
-	// Check if docker is available in PATH (skip for dry-run mode)
-	if !CLI.Dry {
-		_, err := exec.LookPath("docker")
-		if err != nil {
-			fmt.Fprintln(os.Stderr, "Error: 'docker' command not found in PATH.")
-			fmt.Fprintln(os.Stderr, "Please install Docker and ensure it's available in your PATH.")
-			fmt.Fprintln(os.Stderr, "Run with --dry flag to see commands without requiring Docker.")
-			os.Exit(int(ExitStatusDockerNotFound))
-		}
+	// cancelling running docker commands on Ctrl+C, so the created container
+	// can be rolled back
+	runCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		// restoring the default behavior, so the second signal terminates
+		// immediately, e.g. if the rollback hangs
+		<-runCtx.Done()
+		stop()
+	}()
+
+	switch ctx.Selected().Name {
+	case "list":
+		runList(runCtx, CLI.List)
+	case "destroy":
+		runDestroy(runCtx, CLI.Destroy)
+	default:
+		runCreate(runCtx, createArgs)
+	}
+}
+
+func runCreate(ctx context.Context, args CliArgs) {
+	out := io.Writer(os.Stdout)
+	if isJSONOutput() || (args.Format != "" && !args.Dry.Enabled()) {
+		// keeping stdout clean, so the result can be captured by scripts
+		out = os.Stderr
+	}
+	// exported definition doesn't require the runtime to be available
+	var backend dbcreator.Backend
+	runtime := getRuntime(true, false)
+	if args.Emit == "" {
+		backend = getBackend(ctx, args.Dry, args.Verbose, out, true)
+		runtime = backend.Runtime()
+	}
+	if isJSONOutput() {
+		// prompts would break machine-readable output
+		args.NonInteractive = true
 	}
 
-	creator, err := getCreator(CLI.Type, CLI.NonInteractive)
+	creator, err := getCreator(args)
 	if err != nil {
-		fmt.Println(err)
-		os.Exit(int(ExitStatusFailedToGetCreator))
+		exitWithError(ExitStatusFailedToGetCreator, err)
 	}
-	options, err := getOptions(creator, CLI)
+	options, err := getOptions(creator, runtime, args)
 	if err != nil {
-		fmt.Println(err)
-		os.Exit(int(ExitStatusFailedToGetCreator))
+		exitWithError(ExitStatusFailedToGetCreator, err)
+	}
+
+	connectionInfo := creator.GetConnectionInfo(options)
+	if args.Format != "" {
+		if _, ok := connectionInfo.ConnectionString(args.Format); !ok {
+			exitWithError(ExitStatusFailedToGetCreator, fmt.Errorf(
+				"unknown connection string format '%s', must be one of: %s",
+				args.Format, strings.Join(connectionInfo.Formats(), ", "),
+			))
+		}
 	}
 
-	err = creator.Create(dbcreator.NewShell(options.DryRun, options.Verbose), options)
+	if args.Emit != "" {
+		runEmit(creator, runtime, options, connectionInfo, args)
+		return
+	}
+
+	tx := dbcreator.NewTransaction(backend)
+	tx.TrackVolume(ctx, options.Volume)
+	containerID, err := creator.Create(ctx, backend, tx, options)
 	if err != nil {
-		fmt.Println(err)
-		os.Exit(int(ExitStatusFailedToCreateContainer))
+		finishFailedCreation(tx, args.KeepOnFailure)
+		if ctx.Err() != nil {
+			exitWithError(ExitStatusInterrupted, fmt.Errorf("interrupted at step '%s'", tx.CurrentStep()))
+		}
+		exitWithError(ExitStatusFailedToCreateContainer, err)
+	}
+	tx.Commit()
+
+	mergeEnvFile(args, connectionInfo, "container is created")
+
+	if isJSONOutput() {
+		printJSON(makeCreateResult(containerID, creator.GetDefaultOpts().Image, args.Type, options, connectionInfo))
+	} else if !options.DryRun {
+		printConnectionInfo(connectionInfo, args.Format, options.GeneratedPassword)
+	}
+}
+
+// mergeEnvFile writes the credentials into the dotenv file, if it's requested
+func mergeEnvFile(args CliArgs, info dbcreator.ConnectionInfo, done string) {
+	if args.EnvFile == "" || args.Dry.Enabled() {
+		return
+	}
+	if err := dotenv.Merge(args.EnvFile, makeEnvEntries(info, args.EnvPrefix)); err != nil {
+		exitWithError(ExitStatusFailedToWriteEnvFile, fmt.Errorf("%s, but %w", done, err))
+	}
+}
+
+func makeEnvEntries(info dbcreator.ConnectionInfo, prefix string) []dotenv.Entry {
+	uri, _ := info.ConnectionString("uri")
+	return []dotenv.Entry{
+		{Key: prefix + "DATABASE_URL", Value: uri},
+		{Key: prefix + "DB_HOST", Value: info.Host},
+		{Key: prefix + "DB_PORT", Value: strconv.Itoa(int(info.Port))},
+		{Key: prefix + "DB_USER", Value: info.User},
+		{Key: prefix + "DB_PASSWORD", Value: info.Password},
+		{Key: prefix + "DB_NAME", Value: info.Database},
+	}
+}
+
+// printConnectionInfo prints the connection strings, or only the one in the
+// specified format. Generated password is printed explicitly, as it's not
+// stored anywhere else.
+func printConnectionInfo(info dbcreator.ConnectionInfo, format string, generatedPassword bool) {
+	if format != "" {
+		value, _ := info.ConnectionString(format)
+		fmt.Println(value)
+		return
+	}
+	if generatedPassword {
+		fmt.Printf("\nGenerated password: %s\n", info.Password)
+	}
+	fmt.Println("\nConnection strings:")
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, cs := range info.ConnectionStrings {
+		_, _ = fmt.Fprintf(w, "  %s\t%s\n", cs.Format, cs.Value)
 	}
+	_ = w.Flush()
+}
+
+// getRuntime returns the container runtime, selected by the --runtime flag or
+// detected from PATH, exiting if it's not available. In dry run mode runtime
+// doesn't have to be installed, falling back to docker.
+func getRuntime(dryRun bool, suggestDryRun bool) dbcreator.Runtime {
+	var runtime dbcreator.Runtime
+	var err error
+	if CLI.Runtime != "" {
+		runtime, err = dbcreator.RuntimeByName(CLI.Runtime)
+		if err == nil && !dryRun && !runtime.IsAvailable() {
+			err = fmt.Errorf("'%s' command not found in PATH", runtime.Name)
+		}
+	} else {
+		runtime, err = dbcreator.DetectRuntime()
+		if err != nil && dryRun {
+			return dbcreator.Docker
+		}
+	}
+	if err != nil {
+		exitRuntimeNotFound(err, suggestDryRun)
+	}
+	return runtime
+}
+
+func exitRuntimeNotFound(err error, suggestDryRun bool) {
+	if isJSONOutput() {
+		exitWithError(ExitStatusDockerNotFound, err)
+	}
+	fmt.Fprintf(os.Stderr, "Error: %s.\n", err)
+	fmt.Fprintln(os.Stderr, "Please install Docker, Podman or nerdctl and ensure it's available in your PATH.")
+	if suggestDryRun {
+		fmt.Fprintln(os.Stderr, "Run with --dry flag to see commands without requiring a container runtime.")
+	}
+	os.Exit(int(ExitStatusDockerNotFound))
 }
 
 var theme *huh.Theme = huh.ThemeBase16()
 
-func getCreator(dbType string, nonInteractive bool) (dbcreator.DBCreator, error) {
+func getCreator(args CliArgs) (dbcreator.DBCreator, error) {
+	dbType := args.Type
 	if dbType != "" {
-		return makeCreatorByID(dbType)
+		return makeCreatorByID(dbType, args)
 	}
-	if nonInteractive {
+	if args.NonInteractive {
 		return nil, fmt.Errorf("must supply database type in non-interactive mode")
 	}
 	err := huh.NewForm(huh.NewGroup(
@@ -117,39 +315,122 @@ func getCreator(dbType string, nonInteractive bool) (dbcreator.DBCreator, error)
 	if err != nil {
 		return nil, err
 	}
-	return makeCreatorByID(dbType)
+	return makeCreatorByID(dbType, args)
 }
 
-func makeCreatorByID(dbType string) (dbcreator.DBCreator, error) {
+// makeCreatorByID creates the creator of the type, configured with its
+// engine-specific flags
+func makeCreatorByID(dbType string, args CliArgs) (dbcreator.DBCreator, error) {
+	if flags := mysqlFlags(args); dbType != "mysql" && len(flags) > 0 {
+		fmt.Fprintf(os.Stderr, "This DB type doesn't support MySQL configuration, so provided %s arguments are ignored\n", strings.Join(flags, ", "))
+	}
+	if flags := redisFlags(args); dbType != "redis" && len(flags) > 0 {
+		fmt.Fprintf(os.Stderr, "This DB type doesn't support Redis configuration, so provided %s arguments are ignored\n", strings.Join(flags, ", "))
+	}
+	if dbType != "mongo" && args.ReplicaSet != "" {
+		fmt.Fprintln(os.Stderr, "This DB type doesn't support replica sets, so provided --replica-set argument is ignored")
+	}
 	switch dbType {
 	case "postgres":
 		return postgres.Creator{}, nil
 	case "mssql":
 		return mssql.Creator{}, nil
 	case "mysql":
-		return mysql.Creator{}, nil
+		policy, err := mysql.ParsePasswordPolicy(args.PasswordPolicy)
+		if err != nil {
+			return nil, err
+		}
+		return mysql.Creator{
+			PasswordPolicy:     policy,
+			UserHost:           args.UserHost,
+			AuthPlugin:         args.AuthPlugin,
+			RandomRootPassword: args.RandomRootPassword,
+		}, nil
 	case "mongo":
-		return mongo.Creator{}, nil
+		return mongo.Creator{ReplicaSet: string(args.ReplicaSet)}, nil
 	case "redis":
-		return redis.Creator{}, nil
+		return redis.Creator{
+			ACLRules:        args.ACL,
+			Persistence:     args.Persistence,
+			Save:            args.Save,
+			AppendFsync:     args.Appendfsync,
+			MaxMemory:       args.Maxmemory,
+			MaxMemoryPolicy: args.MaxmemoryPolicy,
+			ConfigFile:      args.RedisConf,
+		}, nil
 	}
 	return nil, fmt.Errorf("unknown db type '%s'. Must be one of 'postgres', 'mssql', 'mysql', 'mongo', or 'redis'", dbType)
 }
 
+// mysqlFlags returns the names of provided MySQL-only flags
+func mysqlFlags(args CliArgs) []string {
+	flags := providedFlags([]namedFlag{
+		{"--password-policy", args.PasswordPolicy},
+		{"--user-host", args.UserHost},
+		{"--auth-plugin", args.AuthPlugin},
+	})
+	if args.RandomRootPassword {
+		flags = append(flags, "--random-root-password")
+	}
+	return flags
+}
+
+// redisFlags returns the names of provided Redis-only flags
+func redisFlags(args CliArgs) []string {
+	return providedFlags([]namedFlag{
+		{"--acl", args.ACL},
+		{"--persistence", args.Persistence},
+		{"--save", args.Save},
+		{"--appendfsync", args.Appendfsync},
+		{"--maxmemory", args.Maxmemory},
+		{"--maxmemory-policy", args.MaxmemoryPolicy},
+		{"--redis-conf", args.RedisConf},
+	})
+}
+
+type namedFlag struct {
+	name  string
+	value string
+}
+
+func providedFlags(flags []namedFlag) []string {
+	var names []string
+	for _, flag := range flags {
+		if flag.value != "" {
+			names = append(names, flag.name)
+		}
+	}
+	return names
+}
+
 const defaultDBName = "db"
 
+// password choices of the wizard
+const (
+	passwordEnter    = "enter"
+	passwordGenerate = "generate"
+	passwordDefault  = "default"
+	passwordNone     = "none"
+)
+
+// runWizard asks for the missing options, reporting whether the user chose to
+// generate a random password
 func runWizard(
 	capabilities dbcreator.Capabilities,
 	validatePassword func(string) error,
 	defaultContainerName string,
 	defaults dbcreator.DefaultOpts,
 	opts *dbcreator.CreateOptions,
-) error {
+) (bool, error) {
 	// We're not setting any values for the fields, opting out for placeholder --
 	// in case user wants to modify the default value, they don't need to erase the current value.
 	// On a cons side, we need to explicitly check for values afterwards. We're not doing that in
 	// the runWizard, as this has to be done for non-interactive mode as well anyway.
 	fields := make([]huh.Field, 0)
+	// password input is shown on a separate page, only if the user chose to
+	// enter it
+	var passwordGroup *huh.Group
+	passwordChoice := passwordEnter
 	if capabilities.DatabaseName && opts.Database == "" {
 		fields = append(fields, huh.NewInput().
 			Title("Database Name?").
@@ -157,29 +438,45 @@ func runWizard(
 			Value(&opts.Database),
 		)
 	}
-	if capabilities.UserPassword {
-		if opts.User == "" {
-			fields = append(fields, huh.NewInput().
-				Title("Database User?").
-				Placeholder(defaults.User).
-				Value(&opts.User),
-			)
+	if capabilities.UserPassword && opts.User == "" {
+		fields = append(fields, huh.NewInput().
+			Title("Database User?").
+			Placeholder(defaults.User).
+			Value(&opts.User),
+		)
+	}
+	if capabilities.Auth() && opts.Password == "" {
+		choices := []huh.Option[string]{
+			huh.NewOption("Enter a password", passwordEnter),
+			huh.NewOption("Generate a random password", passwordGenerate),
+		}
+		if defaults.Password != "" {
+			choices = append(choices, huh.NewOption(fmt.Sprintf("Use the default (%s)", defaults.Password), passwordDefault))
 		}
-		if opts.Password == "" {
-			fields = append(fields, huh.NewInput().
-				Title("Database password?").
-				EchoMode(huh.EchoModePassword).
-				Validate(func(val string) error {
-					// if value is empty we're omitting the validation as the default value will be set later
-					if val == "" {
-						return nil
-					}
-					return validatePassword(val)
-				}).
-				Placeholder(defaults.Password).
-				Value(&opts.Password),
-			)
+		// a user can't be created without a password
+		if capabilities.PasswordOnly && opts.User == "" {
+			choices = append(choices, huh.NewOption("No password (auth is disabled)", passwordNone))
 		}
+		fields = append(fields, huh.NewSelect[string]().
+			Title("Database password?").
+			Options(choices...).
+			Value(&passwordChoice),
+		)
+		passwordGroup = huh.NewGroup(huh.NewInput().
+			Title("Database password?").
+			EchoMode(huh.EchoModePassword).
+			Validate(func(val string) error {
+				// if value is empty we're omitting the validation as the default value will be set later
+				if val == "" && defaults.Password != "" {
+					return nil
+				}
+				return validatePassword(val)
+			}).
+			Placeholder(defaults.Password).
+			Value(&opts.Password),
+		).WithHideFunc(func() bool {
+			return passwordChoice != passwordEnter
+		})
 	}
 	if opts.ContainerName == "" {
 		fields = append(fields, huh.NewInput().
@@ -190,27 +487,40 @@ func runWizard(
 		)
 	}
 
-	return huh.NewForm(huh.NewGroup(fields...).WithTheme(theme)).Run()
+	groups := []*huh.Group{huh.NewGroup(fields...)}
+	if passwordGroup != nil {
+		groups = append(groups, passwordGroup)
+	}
+	err := huh.NewForm(groups...).WithTheme(theme).Run()
+	return passwordChoice == passwordGenerate, err
 }
 
-func getOptions(creator dbcreator.DBCreator, args CliArgs) (dbcreator.CreateOptions, error) {
+func getOptions(creator dbcreator.DBCreator, runtime dbcreator.Runtime, args CliArgs) (dbcreator.CreateOptions, error) {
 	capabilities := creator.GetCapabilities()
 	defaultOpts := creator.GetDefaultOpts()
 	opts := dbcreator.CreateOptions{
 		Database:      args.Database,
 		User:          args.User,
 		Password:      args.Password,
+		RootPassword:  args.RootPassword,
 		ContainerName: args.ContainerName,
 		DockerTag:     args.Tag,
+		Volume:        args.Volume,
+		InitScripts:   args.Init,
 		Verbose:       args.Verbose,
-		DryRun:        args.Dry,
+		DryRun:        args.Dry.Enabled(),
+		// keeping stdout clean for the dry run script
+		Quiet:       isJSONOutput() || args.Dry.Enabled(),
+		Wait:        args.Wait,
+		Timeout:     args.Timeout,
+		ToolVersion: getVersion(),
 	}
 	// Setting non-interactive-only defaults
 	if len(args.Port) == 0 {
 		if args.Public {
 			opts.Ports = []string{fmt.Sprintf("%d", defaultOpts.Port)}
 		} else {
-			opts.Ports = getLocalhostBindings(defaultOpts.Port)
+			opts.Ports = getLocalhostBindings(defaultOpts.Port, runtime.IPv6PortBindings)
 		}
 	} else {
 		opts.Ports = args.Port
@@ -219,21 +529,44 @@ func getOptions(creator dbcreator.DBCreator, args CliArgs) (dbcreator.CreateOpti
 		opts.DockerTag = defaultOpts.DockerTag
 	}
 
+	if args.GeneratePassword && capabilities.Auth() {
+		if opts.Password != "" {
+			return opts, fmt.Errorf("--password and --generate-password flags are mutually exclusive")
+		}
+		if err := generatePassword(creator, &opts); err != nil {
+			return opts, err
+		}
+	}
+
 	// validating existing password first if it's there for early exit
-	if opts.Password != "" {
+	if opts.Password != "" && !opts.GeneratedPassword {
 		err := creator.ValidatePassword(opts.Password)
 		if err != nil {
 			return opts, fmt.Errorf("provided password does not meet the requirements: %w", err)
 		}
 	}
 
+	if opts.RootPassword != "" {
+		if !capabilities.RootPassword {
+			fmt.Fprintln(os.Stderr, "This DB type doesn't support a separate root password, so provided --root-password argument is ignored")
+			opts.RootPassword = ""
+		} else if err := creator.ValidatePassword(opts.RootPassword); err != nil {
+			return opts, fmt.Errorf("provided root password does not meet the requirements: %w", err)
+		}
+	}
+
 	randomContainerName := randomname.Generate()
 
 	if !args.NonInteractive {
-		err := runWizard(capabilities, creator.ValidatePassword, randomContainerName, defaultOpts, &opts)
+		generate, err := runWizard(capabilities, creator.ValidatePassword, randomContainerName, defaultOpts, &opts)
 		if err != nil {
 			return opts, fmt.Errorf("error running the wizard: %w", err)
 		}
+		if generate {
+			if err := generatePassword(creator, &opts); err != nil {
+				return opts, err
+			}
+		}
 	}
 
 	// Setting default values
@@ -254,20 +587,31 @@ func getOptions(creator dbcreator.DBCreator, args CliArgs) (dbcreator.CreateOpti
 		fmt.Fprintln(os.Stderr, "This DB type doesn't support database name, so provided argument is ignored")
 	}
 
+	if !capabilities.InitScripts && len(opts.InitScripts) > 0 {
+		return opts, fmt.Errorf("this DB type doesn't support init scripts")
+	}
+
 	if capabilities.UserPassword {
 		if args.NonInteractive {
 			if opts.User == "" {
 				return opts, fmt.Errorf("db username is required in non-interactive mode, but not provided")
 			}
-			if defaultOpts.Password == "" {
-				return opts, fmt.Errorf("password is required in non-interactive mode, but not provided")
+			if opts.Password == "" {
+				return opts, fmt.Errorf("password is required in non-interactive mode, but not provided, use --password or --generate-password")
 			}
 		}
+	} else if capabilities.PasswordOnly {
+		if opts.User != "" && opts.Password == "" {
+			return opts, fmt.Errorf("password is required for the db user, use --password or --generate-password")
+		}
+		if opts.Password == "" && args.Public {
+			fmt.Fprintln(os.Stderr, "The database is exposed to the outside world without a password, consider using --password or --generate-password")
+		}
 	} else {
 		if opts.User != "" {
 			fmt.Fprintln(os.Stderr, "This DB type doesn't support user/password for its auth, so provided username argument is ignored")
 		}
-		if opts.Password != "" {
+		if opts.Password != "" || args.GeneratePassword {
 			fmt.Fprintln(os.Stderr, "This DB type doesn't support user/password for its auth, so provided password argument is ignored")
 		}
 	}
@@ -275,6 +619,16 @@ func getOptions(creator dbcreator.DBCreator, args CliArgs) (dbcreator.CreateOpti
 	return opts, nil
 }
 
+func generatePassword(creator dbcreator.DBCreator, opts *dbcreator.CreateOptions) error {
+	password, err := dbcreator.GeneratePassword(creator.ValidatePassword)
+	if err != nil {
+		return err
+	}
+	opts.Password = password
+	opts.GeneratedPassword = true
+	return nil
+}
+
 var (
 	containerFirstChar   *regexp.Regexp = regexp.MustCompile(`^[a-zA-Z0-9]`)
 	containerNamePattern *regexp.Regexp = regexp.MustCompile(`^[a-zA-Z0-9_.-]+$`)
@@ -300,7 +654,11 @@ func ValidateContainerName(name string) error {
 }
 
 func helpPrinter(options kong.HelpOptions, ctx *kong.Context) error {
-	if err := kong.DefaultHelpPrinter(options, ctx); err != nil {
+	if ctx.Selected() == nil {
+		if err := printTopLevelHelp(options, ctx); err != nil {
+			return err
+		}
+	} else if err := kong.DefaultHelpPrinter(options, ctx); err != nil {
 		return err
 	}
 
@@ -311,6 +669,11 @@ func helpPrinter(options kong.HelpOptions, ctx *kong.Context) error {
 	_, _ = fmt.Fprintf(w, "  %s\tRun in wizard mode\n", ctx.Model.Name)
 	_, _ = fmt.Fprintf(w, "  %s --dry\tDry-run in wizard mode\n", ctx.Model.Name)
 	_, _ = fmt.Fprintf(w, "  %s -t mssql -u app_user\tCreate a MsSQL database using provided username\n", ctx.Model.Name)
+	_, _ = fmt.Fprintf(w, "  %s -t postgres -n -f uri\tCreate a Postgres database, printing only its connection URI\n", ctx.Model.Name)
+	_, _ = fmt.Fprintf(w, "  INIT_DOCKER_DB_TYPE=redis %s -n\tCreate a Redis database, configured through environment variables\n", ctx.Model.Name)
+	_, _ = fmt.Fprintf(w, "  %s list\tList containers created by %s\n", ctx.Model.Name, ctx.Model.Name)
+	_, _ = fmt.Fprintf(w, "  %s destroy --older-than 7d\tRemove containers created more than a week ago\n", ctx.Model.Name)
+	_, _ = fmt.Fprintf(w, "  %s compose -t postgres -n\tAdd a Postgres service to compose.yaml instead of running it\n", ctx.Model.Name)
 
 	if err := w.Flush(); err != nil {
 		return err
@@ -319,15 +682,55 @@ func helpPrinter(options kong.HelpOptions, ctx *kong.Context) error {
 	return nil
 }
 
+// width of the wrapped help text
+const helpWidth = 80
+
+// printTopLevelHelp prints the help of the default create command, so its
+// flags are visible without the command's name, followed by the list of all
+// of the commands
+func printTopLevelHelp(options kong.HelpOptions, ctx *kong.Context) error {
+	createCtx, err := kong.Trace(ctx.Kong, []string{"create"})
+	if err != nil {
+		return err
+	}
+	if err := kong.DefaultHelpPrinter(options, createCtx); err != nil {
+		return err
+	}
+
+	fmt.Println("\nCommands:")
+	for _, cmd := range ctx.Model.Leaves(true) {
+		fmt.Printf("  %s\n", cmd.Summary())
+		// wrapping the help the same way as kong does
+		line := "   "
+		for _, word := range strings.Fields(cmd.Help) {
+			if len(line)+len(word) >= helpWidth {
+				fmt.Println(line)
+				line = "   "
+			}
+			line += " " + word
+		}
+		fmt.Printf("%s\n\n", line)
+	}
+	fmt.Printf("Run \"%s <command> --help\" for more information on a command.\n", ctx.Model.Name)
+	return nil
+}
+
 func showVersion() {
-	if ldVersion != "" {
-		fmt.Printf("%s\n", ldVersion)
+	version := getVersion()
+	if version == "" {
+		fmt.Println("Build information not available")
 		return
 	}
+	fmt.Printf("%s\n", version)
+}
+
+func getVersion() string {
+	if ldVersion != "" {
+		return ldVersion
+	}
 	info, ok := debug.ReadBuildInfo()
 	if !ok {
-		fmt.Println("Build information not available")
-		return
+		return ""
 	}
 	buildInfoVersion := info.Main.Version
 	if buildInfoVersion == "(devel)" {
@@ -341,27 +744,29 @@ func showVersion() {
 				dirty = setting.Value == "true"
 			}
 		}
-		buildInfoVersion += " " + commit
+		if commit != "" {
+			buildInfoVersion += " " + commit
+		}
 		if dirty {
 			buildInfoVersion += " dirty"
 		}
 	}
 
-	fmt.Printf("%s\n", buildInfoVersion)
+	return buildInfoVersion
 }
 
-func getLocalhostBindings(port uint16) []string {
+func getLocalhostBindings(port uint16, ipV6Supported bool) []string {
 	var bindings []string
 
 	ipV4 := isIPv4Available()
-	ipV6 := isIPv6Available()
+	ipV6 := ipV6Supported && isIPv6Available()
 	// IPv4 loopback OR fallback value
 	if ipV4 || (!ipV4 && !ipV6) {
 		bindings = append(bindings, fmt.Sprintf("127.0.0.1:%d", port))
 	}
 
 	// IPv6 loopback
-	if isIPv6Available() {
+	if ipV6 {
 		bindings = append(bindings, fmt.Sprintf("[::1]:%d", port))
 	}
 
@@ -0,0 +1,62 @@
+// Package managed provides access to the containers, previously created by
+// the tool, identified by their labels
+package managed
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/religiosa1/init-docker-db/dbcreator"
+)
+
+// Container is a docker container created by the tool
+type Container struct {
+	ID     string
+	Name   string
+	Engine string
+	Image  string
+	Ports  string
+	Status string
+	// Creation time as stamped on the container label, zero if label is malformed
+	CreatedAt time.Time
+}
+
+// Tag returns the docker tag part of the container's image
+func (c Container) Tag() string {
+	// colon can also be a part of the registry host:port, so only looking
+	// at the last path segment
+	lastSegment := c.Image[strings.LastIndex(c.Image, "/")+1:]
+	if idx := strings.LastIndex(lastSegment, ":"); idx != -1 {
+		return lastSegment[idx+1:]
+	}
+	return "latest"
+}
+
+// List returns all containers (both running and stopped) created by the tool
+func List(ctx context.Context, backend dbcreator.Backend) ([]Container, error) {
+	summaries, err := backend.List(ctx, dbcreator.LabelEngine)
+	if err != nil {
+		return nil, fmt.Errorf("error listing containers: %w", err)
+	}
+	containers := make([]Container, len(summaries))
+	for i, s := range summaries {
+		containers[i] = fromSummary(s)
+	}
+	return containers, nil
+}
+
+func fromSummary(s dbcreator.ContainerSummary) Container {
+	// malformed or missing creation time is not critical, leaving zero value
+	createdAt, _ := time.Parse(time.RFC3339, s.Labels[dbcreator.LabelCreatedAt])
+	return Container{
+		ID:        s.ID,
+		Name:      s.Name,
+		Engine:    s.Labels[dbcreator.LabelEngine],
+		Image:     s.Image,
+		Ports:     s.Ports,
+		Status:    s.Status,
+		CreatedAt: createdAt,
+	}
+}
@@ -0,0 +1,58 @@
+package managed
+
+import (
+	"testing"
+	"time"
+
+	"github.com/religiosa1/init-docker-db/dbcreator"
+)
+
+func TestContainerTag(t *testing.T) {
+	cases := [...]struct {
+		image string
+		tag   string
+	}{
+		{"postgres:16-alpine", "16-alpine"},
+		{"postgres", "latest"},
+		{"mcr.microsoft.com/mssql/server:2022-latest", "2022-latest"},
+		{"localhost:5000/redis", "latest"},
+		{"localhost:5000/redis:7", "7"},
+	}
+	for _, tt := range cases {
+		t.Run(tt.image, func(t *testing.T) {
+			got := Container{Image: tt.image}.Tag()
+			if got != tt.tag {
+				t.Errorf("Unexpected value, want %s, got %s", tt.tag, got)
+			}
+		})
+	}
+}
+
+func Test_fromSummary(t *testing.T) {
+	t.Run("maps labels to the container fields", func(t *testing.T) {
+		got := fromSummary(dbcreator.ContainerSummary{
+			ID:     "abc123",
+			Name:   "foo-bar",
+			Image:  "postgres:latest",
+			Ports:  "127.0.0.1:5432->5432/tcp",
+			Status: "Up 2 hours",
+			Labels: map[string]string{
+				dbcreator.LabelEngine:    "postgres",
+				dbcreator.LabelCreatedAt: "2025-11-20T10:00:00Z",
+			},
+		})
+		if got.Name != "foo-bar" || got.Engine != "postgres" || got.Status != "Up 2 hours" {
+			t.Errorf("Unexpected container value: %+v", got)
+		}
+		if want := time.Date(2025, 11, 20, 10, 0, 0, 0, time.UTC); !got.CreatedAt.Equal(want) {
+			t.Errorf("Unexpected creation time, want %s, got %s", want, got.CreatedAt)
+		}
+	})
+
+	t.Run("leaves zero creation time for missing label", func(t *testing.T) {
+		got := fromSummary(dbcreator.ContainerSummary{ID: "def456", Labels: map[string]string{}})
+		if !got.CreatedAt.IsZero() {
+			t.Errorf("Expected zero creation time for missing label, got %s", got.CreatedAt)
+		}
+	})
+}
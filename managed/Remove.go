@@ -0,0 +1,27 @@
+package managed
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/religiosa1/init-docker-db/dbcreator"
+)
+
+// Remove stops the provided containers and removes them alongside with their
+// anonymous volumes. Named volumes and bind mounts are left intact.
+func Remove(ctx context.Context, backend dbcreator.Backend, containers []Container) error {
+	if len(containers) == 0 {
+		return nil
+	}
+	ids := make([]string, len(containers))
+	for i, c := range containers {
+		ids[i] = c.ID
+	}
+	if err := backend.Stop(ctx, ids...); err != nil {
+		return fmt.Errorf("error stopping containers: %w", err)
+	}
+	if err := backend.Remove(ctx, ids...); err != nil {
+		return fmt.Errorf("error removing containers: %w", err)
+	}
+	return nil
+}
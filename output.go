@@ -0,0 +1,139 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/religiosa1/init-docker-db/dbcreator"
+	"github.com/religiosa1/init-docker-db/managed"
+)
+
+const (
+	OutputText = "text"
+	OutputJSON = "json"
+)
+
+func isJSONOutput() bool {
+	return CLI.Output == OutputJSON
+}
+
+type errorResult struct {
+	Error errorDetails `json:"error"`
+}
+
+type errorDetails struct {
+	Message    string     `json:"message"`
+	ExitStatus ExitStatus `json:"exitStatus"`
+}
+
+// exitWithError prints the error in the selected output format and exits
+// with the provided status
+func exitWithError(status ExitStatus, err error) {
+	if isJSONOutput() {
+		printJSON(errorResult{Error: errorDetails{
+			Message:    err.Error(),
+			ExitStatus: status,
+		}})
+	} else {
+		fmt.Println(err)
+	}
+	os.Exit(int(status))
+}
+
+// printJSON prints the value as a single indented JSON document to stdout
+func printJSON(v any) {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		fmt.Fprintf(os.Stderr, "error encoding JSON output: %s\n", err)
+	}
+}
+
+type createResult struct {
+	ContainerID       string            `json:"containerId"`
+	Name              string            `json:"name"`
+	Engine            string            `json:"engine"`
+	Image             string            `json:"image"`
+	Ports             []string          `json:"ports"`
+	Volume            string            `json:"volume,omitempty"`
+	Host              string            `json:"host"`
+	Port              uint16            `json:"port"`
+	User              string            `json:"user,omitempty"`
+	Password          string            `json:"password,omitempty"`
+	PasswordGenerated bool              `json:"passwordGenerated,omitempty"`
+	Database          string            `json:"database,omitempty"`
+	ConnectionStrings map[string]string `json:"connectionStrings"`
+	DryRun            bool              `json:"dryRun,omitempty"`
+}
+
+func makeCreateResult(
+	containerID string,
+	image string,
+	engine string,
+	opts dbcreator.CreateOptions,
+	info dbcreator.ConnectionInfo,
+) createResult {
+	if opts.DryRun {
+		// container isn't created, the printed script captures its ID itself
+		containerID = ""
+	}
+	connectionStrings := make(map[string]string, len(info.ConnectionStrings))
+	for _, cs := range info.ConnectionStrings {
+		connectionStrings[cs.Format] = cs.Value
+	}
+	return createResult{
+		ContainerID:       containerID,
+		Name:              opts.ContainerName,
+		Engine:            engine,
+		Image:             fmt.Sprintf("%s:%s", image, opts.DockerTag),
+		Ports:             opts.Ports,
+		Volume:            opts.Volume,
+		Host:              info.Host,
+		Port:              info.Port,
+		User:              info.User,
+		Password:          info.Password,
+		PasswordGenerated: opts.GeneratedPassword,
+		Database:          info.Database,
+		ConnectionStrings: connectionStrings,
+		DryRun:            opts.DryRun,
+	}
+}
+
+type listResult struct {
+	Containers []containerResult `json:"containers"`
+}
+
+type destroyResult struct {
+	Removed []containerResult `json:"removed"`
+	DryRun  bool              `json:"dryRun,omitempty"`
+}
+
+type containerResult struct {
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	Engine    string     `json:"engine"`
+	Image     string     `json:"image"`
+	Ports     string     `json:"ports"`
+	Status    string     `json:"status"`
+	CreatedAt *time.Time `json:"createdAt"`
+}
+
+func makeContainerResults(containers []managed.Container) []containerResult {
+	results := make([]containerResult, len(containers))
+	for i, c := range containers {
+		results[i] = containerResult{
+			ID:     c.ID,
+			Name:   c.Name,
+			Engine: c.Engine,
+			Image:  c.Image,
+			Ports:  c.Ports,
+			Status: c.Status,
+		}
+		if !c.CreatedAt.IsZero() {
+			results[i].CreatedAt = &c.CreatedAt
+		}
+	}
+	return results
+}
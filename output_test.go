@@ -0,0 +1,42 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/religiosa1/init-docker-db/creators/postgres"
+	"github.com/religiosa1/init-docker-db/dbcreator"
+)
+
+func TestMakeCreateResultDryRun(t *testing.T) {
+	var script bytes.Buffer
+	shell := dbcreator.NewShell(true, false).WithRuntime(dbcreator.Docker).WithOutput(&script)
+	backend := dbcreator.NewCLIBackend(shell)
+	creator := postgres.Creator{}
+	opts := dbcreator.CreateOptions{
+		ContainerName: "pg", User: "postgres", Password: "pass", Database: "db",
+		DockerTag: "latest", DryRun: true, Wait: true,
+	}
+	containerID, err := creator.Create(context.Background(), backend, dbcreator.NewTransaction(backend), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := makeCreateResult(containerID, "postgres", "postgres", opts, creator.GetConnectionInfo(opts))
+	out, err := json.Marshal(result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(out, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded["containerId"] != "" || decoded["dryRun"] != true {
+		t.Errorf("Expected an empty container ID in the dry run result, got %s", out)
+	}
+	if !bytes.Contains(script.Bytes(), []byte(`"$CONTAINER_ID"`)) {
+		t.Errorf("Expected the script to use the captured container ID, got\n%s", script.String())
+	}
+}
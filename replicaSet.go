@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/alecthomas/kong"
+	"github.com/religiosa1/init-docker-db/creators/mongo"
+)
+
+// ReplicaSet is the --replica-set flag value: name of the Mongo replica set,
+// or empty if it's disabled. The flag can be used without a value, as a
+// boolean one, defaulting to rs0, so the name must be passed as
+// --replica-set=NAME.
+type ReplicaSet string
+
+// IsBool allows the flag to be used without a value
+func (r ReplicaSet) IsBool() bool {
+	return true
+}
+
+func (r *ReplicaSet) Decode(ctx *kong.DecodeContext) error {
+	next := ctx.Scan.Peek()
+	if next.Type != kong.FlagValueToken {
+		// otherwise `--replica-set myrs` silently uses the name as the
+		// container's one
+		if arg, ok := next.Value.(string); ok && next.Type == kong.UntypedToken && !strings.HasPrefix(arg, "-") {
+			return fmt.Errorf("replica set name must be passed as --replica-set=%s; to use '%s' as the container name, put it before the flag", arg, arg)
+		}
+		*r = mongo.DefaultReplicaSet
+		return nil
+	}
+	var value string
+	switch v := ctx.Scan.Pop().Value.(type) {
+	case bool:
+		value = fmt.Sprint(v)
+	case string:
+		value = v
+	default:
+		return fmt.Errorf("expected a replica set name or a bool but got %q (%T)", v, v)
+	}
+	switch strings.ToLower(value) {
+	case "true":
+		*r = mongo.DefaultReplicaSet
+	case "false", "":
+		*r = ""
+	default:
+		*r = ReplicaSet(value)
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/alecthomas/kong"
+)
+
+func TestReplicaSetFlag(t *testing.T) {
+	tests := []struct {
+		args []string
+		want ReplicaSet
+		name string
+		err  bool
+	}{
+		{[]string{"--replica-set"}, "rs0", "", false},
+		{[]string{"--replica-set=myrs"}, "myrs", "", false},
+		{[]string{"--replica-set=false"}, "", "", false},
+		{[]string{"--replica-set", "-t", "mongo"}, "rs0", "", false},
+		{[]string{"mydb", "--replica-set"}, "rs0", "mydb", false},
+		// the name is mistaken for the positional argument otherwise
+		{[]string{"--replica-set", "myrs"}, "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			var cli struct {
+				Name       string     `arg:"" optional:""`
+				Type       string     `short:"t"`
+				ReplicaSet ReplicaSet `name:"replica-set"`
+			}
+			_, err := kong.Must(&cli).Parse(tt.args)
+			if (err != nil) != tt.err {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !tt.err && (cli.ReplicaSet != tt.want || cli.Name != tt.name) {
+				t.Errorf("Want %q and name %q, got %q and %q", tt.want, tt.name, cli.ReplicaSet, cli.Name)
+			}
+		})
+	}
+}
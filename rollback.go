@@ -0,0 +1,30 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/religiosa1/init-docker-db/dbcreator"
+)
+
+// finishFailedCreation removes resources created by the failed creation or,
+// if they should be kept, reports them to the user
+func finishFailedCreation(tx *dbcreator.Transaction, keepOnFailure bool) {
+	if keepOnFailure {
+		if resources := tx.Resources(); len(resources) > 0 {
+			fmt.Fprintf(os.Stderr, "Keeping %s for inspection\n", strings.Join(resources, ", "))
+		}
+		return
+	}
+	// not using the creation context, as it can be already cancelled
+	removed, err := tx.Rollback(context.Background())
+	if len(removed) > 0 {
+		fmt.Fprintf(os.Stderr, "Rolled back: removed %s\n", strings.Join(removed, ", "))
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, errors.Join(errors.New("error rolling back the creation"), err))
+	}
+}
@@ -3,22 +3,44 @@ package wait
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math"
+	"math/rand/v2"
 	"time"
 )
 
+// ErrMaxAttempts is returned, when the probe fails Opts.MaxAttempts times
+var ErrMaxAttempts = errors.New("maximum number of attempts reached")
+
 // Opts is  options for wait.For function
 type Opts struct {
+	// delay before the first attempt
 	PreDelay time.Duration
+	// delay after the first failed attempt, 500ms by default
 	MinDelay time.Duration
+	// upper limit of the delay between attempts, 5s by default
 	MaxDelay time.Duration
-	Rate     float64
+	// multiplier of the delay after each failed attempt, 1.5 by default
+	Rate float64
+	// randomizes each delay by up to the provided fraction of it, e.g. 0.2 for
+	// ±20%, so multiple clients don't retry in lockstep; 0 disables jitter
+	Jitter float64
+	// timeout of a single attempt, passed into the probe as its context;
+	// 0 means no timeout besides the parent context
+	AttemptTimeout time.Duration
+	// maximum number of attempts; 0 means unlimited
+	MaxAttempts int
+	// called after each failed attempt, which is going to be retried, with the
+	// attempt number (starting from 1), its error and the delay before the next one
+	OnRetry func(attempt int, err error, delay time.Duration)
 }
 
-// For waits for exp to return without an error, retrying its calls with
-// an increasing exponential backoff
-func For(ctx context.Context, exp func() error, opts Opts) error {
+// For waits for probe to return without an error, retrying its calls with
+// an increasing exponential backoff, until ctx is done or the maximum number
+// of attempts is reached. Returned error wraps both the reason of stopping
+// (ctx.Err() or ErrMaxAttempts) and the last error of the probe.
+func For(ctx context.Context, probe func(ctx context.Context) error, opts Opts) error {
 	if opts.MinDelay == 0 {
 		opts.MinDelay = time.Duration(500) * time.Millisecond
 	}
@@ -38,22 +60,80 @@ func For(ctx context.Context, exp func() error, opts Opts) error {
 	if opts.MaxDelay < opts.MinDelay {
 		return fmt.Errorf("max delay value must be a positive integer bigger than initialDelay, got %d (minDelay = %d)", opts.MaxDelay, opts.MinDelay)
 	}
+	if 0 > opts.Jitter || opts.Jitter > 1 {
+		return fmt.Errorf("jitter must be in range 0 <= JITTER <= 1, got %v", opts.Jitter)
+	}
+	if opts.AttemptTimeout < 0 || opts.MaxAttempts < 0 {
+		return errors.New("attempt timeout and max attempts must not be negative")
+	}
+
+	if err := sleep(ctx, opts.PreDelay); err != nil {
+		return stopError(err, nil)
+	}
 
 	currentDelay := opts.MinDelay
-	for {
-		select {
-		case <-ctx.Done():
-			return fmt.Errorf("operation timed out: %w", ctx.Err())
-		default:
-			if opts.PreDelay != 0 {
-				time.Sleep(opts.PreDelay)
-				opts.PreDelay = 0
-			}
-			if err := exp(); err == nil {
-				return nil
-			}
-			time.Sleep(currentDelay)
-			currentDelay = time.Duration(math.Min(float64(currentDelay)*opts.Rate, float64(opts.MaxDelay)))
+	var lastErr error
+	for attempt := 1; ; attempt++ {
+		lastErr = runAttempt(ctx, probe, opts.AttemptTimeout)
+		if lastErr == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return stopError(ctx.Err(), lastErr)
 		}
+		if opts.MaxAttempts != 0 && attempt >= opts.MaxAttempts {
+			return stopError(ErrMaxAttempts, lastErr)
+		}
+
+		delay := withJitter(currentDelay, opts.Jitter)
+		if opts.OnRetry != nil {
+			opts.OnRetry(attempt, lastErr, delay)
+		}
+		if err := sleep(ctx, delay); err != nil {
+			return stopError(err, lastErr)
+		}
+		currentDelay = time.Duration(math.Min(float64(currentDelay)*opts.Rate, float64(opts.MaxDelay)))
+	}
+}
+
+func runAttempt(ctx context.Context, probe func(ctx context.Context) error, timeout time.Duration) error {
+	if timeout == 0 {
+		return probe(ctx)
+	}
+	attemptCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	return probe(attemptCtx)
+}
+
+// sleep for the duration or until ctx is done, whichever happens first
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func withJitter(d time.Duration, jitter float64) time.Duration {
+	if jitter == 0 {
+		return d
+	}
+	return time.Duration(float64(d) * (1 + jitter*(2*rand.Float64()-1)))
+}
+
+func stopError(reason error, lastErr error) error {
+	msg := "operation cancelled"
+	if errors.Is(reason, context.DeadlineExceeded) {
+		msg = "operation timed out"
+	}
+	if lastErr == nil {
+		return fmt.Errorf("%s: %w", msg, reason)
 	}
+	return fmt.Errorf("%s: %w, last error: %w", msg, reason, lastErr)
 }
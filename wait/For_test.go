@@ -0,0 +1,115 @@
+package wait
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+var errProbe = errors.New("not ready")
+
+var fastOpts = Opts{MinDelay: time.Millisecond, MaxDelay: 2 * time.Millisecond}
+
+func TestForSucceeds(t *testing.T) {
+	attempts := 0
+	var retries []int
+	opts := fastOpts
+	opts.OnRetry = func(attempt int, err error, delay time.Duration) {
+		if !errors.Is(err, errProbe) {
+			t.Errorf("Unexpected retry error %v", err)
+		}
+		retries = append(retries, attempt)
+	}
+	err := For(context.Background(), func(ctx context.Context) error {
+		attempts++
+		if attempts < 3 {
+			return errProbe
+		}
+		return nil
+	}, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attempts != 3 || len(retries) != 2 || retries[0] != 1 || retries[1] != 2 {
+		t.Errorf("Unexpected attempts %d, retries %v", attempts, retries)
+	}
+}
+
+func TestForMaxAttempts(t *testing.T) {
+	attempts := 0
+	opts := fastOpts
+	opts.MaxAttempts = 3
+	err := For(context.Background(), func(ctx context.Context) error {
+		attempts++
+		return errProbe
+	}, opts)
+	if !errors.Is(err, ErrMaxAttempts) || !errors.Is(err, errProbe) {
+		t.Errorf("Expected both max attempts and probe errors, got %v", err)
+	}
+	if attempts != 3 {
+		t.Errorf("Expected 3 attempts, got %d", attempts)
+	}
+}
+
+func TestForTimeout(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	// delay is way longer than the timeout, so it must be interrupted
+	opts := Opts{MinDelay: time.Hour, MaxDelay: time.Hour}
+
+	start := time.Now()
+	err := For(ctx, func(ctx context.Context) error { return errProbe }, opts)
+	if !errors.Is(err, context.DeadlineExceeded) || !errors.Is(err, errProbe) {
+		t.Errorf("Expected both timeout and probe errors, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected to return right after the timeout, took %s", elapsed)
+	}
+}
+
+func TestForAttemptTimeout(t *testing.T) {
+	opts := fastOpts
+	opts.AttemptTimeout = 5 * time.Millisecond
+	opts.MaxAttempts = 2
+	err := For(context.Background(), func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}, opts)
+	if !errors.Is(err, ErrMaxAttempts) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected attempts to time out, got %v", err)
+	}
+}
+
+func TestForJitter(t *testing.T) {
+	for range 100 {
+		d := withJitter(time.Second, 0.2)
+		if d < 800*time.Millisecond || d > 1200*time.Millisecond {
+			t.Fatalf("Delay %s is out of the jitter range", d)
+		}
+	}
+	if d := withJitter(time.Second, 0); d != time.Second {
+		t.Errorf("Expected no jitter, got %s", d)
+	}
+}
+
+func TestForInvalidOpts(t *testing.T) {
+	cases := map[string]Opts{
+		"rate":         {Rate: 0.5},
+		"max delay":    {MinDelay: time.Second, MaxDelay: time.Millisecond},
+		"jitter":       {Jitter: 1.5},
+		"max attempts": {MaxAttempts: -1},
+	}
+	for name, opts := range cases {
+		t.Run(name, func(t *testing.T) {
+			called := false
+			err := For(context.Background(), func(ctx context.Context) error {
+				called = true
+				return nil
+			}, opts)
+			if err == nil || called {
+				t.Errorf("Expected validation error without calling the probe, got %v", err)
+			}
+		})
+	}
+}